@@ -0,0 +1,89 @@
+package binding
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// NegotiateLanguage returns the supported language tag that best matches the request's
+// Accept-Language header, comparing tags case-insensitively. Ranges are tried in order of
+// their q-values; a range matches a supported tag exactly, as a prefix ("en" matches "en-US"),
+// or through its primary language ("en-GB" matches "en"). "*" matches the first supported tag.
+// When nothing matches, or the header is absent, the first supported tag is returned.
+// Returns "" only when supported is empty.
+func NegotiateLanguage(req *http.Request, supported []string) string {
+	if len(supported) == 0 {
+		return ""
+	}
+
+	for _, lang := range acceptLanguages(req.Header.Get("Accept-Language")) {
+		if tag, ok := matchLanguage(lang, supported); ok {
+			return tag
+		}
+	}
+	return supported[0]
+}
+
+// acceptLanguages returns the language ranges of an Accept-Language header ordered by
+// descending q-value, omitting ranges with q=0.
+func acceptLanguages(header string) []string {
+	type weighted struct {
+		lang string
+		q    float64
+	}
+
+	var ranges []weighted
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		lang := strings.TrimSpace(fields[0])
+		if lang == "" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range fields[1:] {
+			k, v, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.EqualFold(k, "q") {
+				if f, err := strconv.ParseFloat(v, 64); err == nil {
+					q = f
+				}
+			}
+		}
+		if q > 0 {
+			ranges = append(ranges, weighted{lang: lang, q: q})
+		}
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].q > ranges[j].q })
+	langs := make([]string, len(ranges))
+	for i, r := range ranges {
+		langs[i] = r.lang
+	}
+	return langs
+}
+
+func matchLanguage(lang string, supported []string) (string, bool) {
+	if lang == "*" {
+		return supported[0], true
+	}
+	for _, tag := range supported {
+		if strings.EqualFold(tag, lang) {
+			return tag, true
+		}
+	}
+	for _, tag := range supported {
+		if len(tag) > len(lang) && strings.EqualFold(tag[:len(lang)], lang) && tag[len(lang)] == '-' {
+			return tag, true
+		}
+	}
+	if primary, _, ok := strings.Cut(lang, "-"); ok {
+		for _, tag := range supported {
+			if strings.EqualFold(tag, primary) {
+				return tag, true
+			}
+		}
+	}
+	return "", false
+}
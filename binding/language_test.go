@@ -0,0 +1,50 @@
+package binding_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/elmq0022/kami/binding"
+)
+
+func TestNegotiateLanguage(t *testing.T) {
+	supported := []string{"en-US", "de", "fr-CA"}
+
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{"exact match", "de", "de"},
+		{"case insensitive", "FR-ca", "fr-CA"},
+		{"q-value preference", "de;q=0.5, fr-CA;q=0.9, en-US;q=0.1", "fr-CA"},
+		{"prefix match", "en", "en-US"},
+		{"primary language match", "de-AT", "de"},
+		{"skips unsupported", "ja, de;q=0.8", "de"},
+		{"q=0 excludes", "de;q=0, fr-CA;q=0.2", "fr-CA"},
+		{"wildcard", "ja, *;q=0.5", "en-US"},
+		{"fallback when nothing matches", "ja, zh", "en-US"},
+		{"fallback without header", "", "en-US"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set("Accept-Language", tt.header)
+			}
+			if got := binding.NegotiateLanguage(req, supported); got != tt.want {
+				t.Errorf("want %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestNegotiateLanguage_NoneSupported(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Language", "en")
+	if got := binding.NegotiateLanguage(req, nil); got != "" {
+		t.Errorf("expected empty result, got %q", got)
+	}
+}
@@ -0,0 +1,64 @@
+package binding
+
+import (
+	"context"
+	"mime/multipart"
+	"net/http"
+)
+
+// DefaultMaxMemory is the number of bytes of a multipart form kept in memory
+// when MultipartOptions.MaxMemory is unset. Larger file parts spill to temporary files.
+const DefaultMaxMemory int64 = 32 << 20
+
+// MultipartOptions configures how multipart forms are parsed.
+type MultipartOptions struct {
+	// MaxMemory is the number of bytes of file parts held in memory before the
+	// remainder is written to temporary files on disk. Zero uses DefaultMaxMemory.
+	MaxMemory int64
+	// MaxFileSize is the largest file accepted, in bytes. StreamUpload uses DefaultMaxUploadSize
+	// when it is zero; FormFile only enforces it when set.
+	MaxFileSize int64
+}
+
+func optionsFrom(opts []MultipartOptions) MultipartOptions {
+	if len(opts) > 0 {
+		return opts[0]
+	}
+	return MultipartOptions{}
+}
+
+func (o MultipartOptions) maxMemory() int64 {
+	if o.MaxMemory <= 0 {
+		return DefaultMaxMemory
+	}
+	return o.MaxMemory
+}
+
+// FormFile parses the request's multipart form and returns the first file for field.
+// Parts that do not fit within the configured MaxMemory are stored in temporary files,
+// which are removed once the request's context is done; the server cancels it when the
+// request completes. Requests whose context never ends, as in tests, must call
+// req.MultipartForm.RemoveAll themselves.
+// Files larger than a configured MaxFileSize fail with ErrUploadTooLarge.
+// Only the first MultipartOptions value is used.
+func FormFile(req *http.Request, field string, opts ...MultipartOptions) (multipart.File, *multipart.FileHeader, error) {
+	o := optionsFrom(opts)
+
+	if req.MultipartForm == nil {
+		if err := req.ParseMultipartForm(o.maxMemory()); err != nil {
+			return nil, nil, err
+		}
+		form := req.MultipartForm
+		context.AfterFunc(req.Context(), func() { form.RemoveAll() })
+	}
+
+	f, fh, err := req.FormFile(field)
+	if err != nil {
+		return nil, nil, err
+	}
+	if o.MaxFileSize > 0 && fh.Size > o.MaxFileSize {
+		f.Close()
+		return nil, nil, ErrUploadTooLarge
+	}
+	return f, fh, nil
+}
@@ -0,0 +1,100 @@
+package binding_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elmq0022/kami/binding"
+)
+
+func TestFormFile_SpillsAboveMaxMemory(t *testing.T) {
+	content := strings.Repeat("x", 64<<10)
+	req := newUploadRequest(t, "photo", "big.bin", content)
+
+	f, fh, err := binding.FormFile(req, "photo", binding.MultipartOptions{MaxMemory: 1 << 10})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer f.Close()
+	defer req.MultipartForm.RemoveAll()
+
+	if _, ok := f.(*os.File); !ok {
+		t.Fatalf("expected part above MaxMemory to spill to a temp file, got %T", f)
+	}
+	if fh.Size != int64(len(content)) {
+		t.Errorf("expected size %d, got %d", len(content), fh.Size)
+	}
+
+	n, err := io.Copy(io.Discard, f)
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+	if n != int64(len(content)) {
+		t.Errorf("expected to read %d bytes, got %d", len(content), n)
+	}
+}
+
+func TestFormFile_KeepsSmallPartsInMemory(t *testing.T) {
+	req := newUploadRequest(t, "photo", "small.bin", "tiny")
+
+	f, _, err := binding.FormFile(req, "photo")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer f.Close()
+
+	if _, ok := f.(*os.File); ok {
+		t.Error("expected small part to stay in memory")
+	}
+}
+
+func TestFormFile_MissingField(t *testing.T) {
+	req := newUploadRequest(t, "photo", "small.bin", "tiny")
+
+	_, _, err := binding.FormFile(req, "avatar")
+	if !errors.Is(err, http.ErrMissingFile) {
+		t.Fatalf("expected http.ErrMissingFile, got %v", err)
+	}
+}
+
+func TestFormFile_RemovesTempFilesWhenRequestEnds(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	req := newUploadRequest(t, "photo", "big.bin", strings.Repeat("x", 64<<10)).WithContext(ctx)
+
+	f, _, err := binding.FormFile(req, "photo", binding.MultipartOptions{MaxMemory: 1 << 10})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tmp, ok := f.(*os.File)
+	if !ok {
+		t.Fatalf("expected a temp file, got %T", f)
+	}
+	f.Close()
+
+	cancel()
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, err := os.Stat(tmp.Name()); errors.Is(err, os.ErrNotExist) {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected temp file %s to be removed once the request ended", tmp.Name())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestFormFile_MaxFileSize(t *testing.T) {
+	req := newUploadRequest(t, "photo", "big.bin", strings.Repeat("x", 100))
+
+	_, _, err := binding.FormFile(req, "photo", binding.MultipartOptions{MaxFileSize: 10})
+	if !errors.Is(err, binding.ErrUploadTooLarge) {
+		t.Fatalf("expected ErrUploadTooLarge, got %v", err)
+	}
+}
@@ -0,0 +1,87 @@
+package binding
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+)
+
+const (
+	// DefaultPageLimit is the page size used when neither the request nor PageDefaults sets one.
+	DefaultPageLimit = 20
+	// DefaultMaxPageLimit caps the page size when PageDefaults.MaxLimit is unset.
+	DefaultMaxPageLimit = 100
+)
+
+// PageDefaults configures Pagination.
+type PageDefaults struct {
+	// Limit is the page size when the request does not ask for one. Zero uses DefaultPageLimit.
+	Limit int
+	// MaxLimit is the largest page size a client may request; larger values are clamped.
+	// Zero uses DefaultMaxPageLimit.
+	MaxLimit int
+}
+
+// Page is a normalized window into a list, ready to pass to a LIMIT/OFFSET query.
+type Page struct {
+	Limit  int
+	Offset int
+}
+
+// Pagination reads the page size from the "limit" or "per_page" query parameter and the
+// position from "offset" or, failing that, the 1-based "page" parameter.
+// The limit is clamped to defaults.MaxLimit. Non-numeric values, a limit or page below 1,
+// a negative offset, and a page whose offset does not fit in an int are errors.
+func Pagination(req *http.Request, defaults PageDefaults) (Page, error) {
+	if defaults.Limit <= 0 {
+		defaults.Limit = DefaultPageLimit
+	}
+	if defaults.MaxLimit <= 0 {
+		defaults.MaxLimit = DefaultMaxPageLimit
+	}
+	query := req.URL.Query()
+
+	page := Page{Limit: defaults.Limit}
+	for _, key := range []string{"limit", "per_page"} {
+		if query.Has(key) {
+			n, err := queryInt(query.Get(key), key, 1)
+			if err != nil {
+				return Page{}, err
+			}
+			page.Limit = n
+			break
+		}
+	}
+	page.Limit = min(page.Limit, defaults.MaxLimit)
+
+	switch {
+	case query.Has("offset"):
+		n, err := queryInt(query.Get("offset"), "offset", 0)
+		if err != nil {
+			return Page{}, err
+		}
+		page.Offset = n
+	case query.Has("page"):
+		n, err := queryInt(query.Get("page"), "page", 1)
+		if err != nil {
+			return Page{}, err
+		}
+		if n-1 > math.MaxInt/page.Limit {
+			return Page{}, fmt.Errorf("binding: page %d is out of range", n)
+		}
+		page.Offset = (n - 1) * page.Limit
+	}
+	return page, nil
+}
+
+func queryInt(s, key string, minimum int) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("binding: %s must be an integer, got %q", key, s)
+	}
+	if n < minimum {
+		return 0, fmt.Errorf("binding: %s must be at least %d, got %d", key, minimum, n)
+	}
+	return n, nil
+}
@@ -0,0 +1,61 @@
+package binding_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/elmq0022/kami/binding"
+)
+
+func TestPagination(t *testing.T) {
+	defaults := binding.PageDefaults{Limit: 25, MaxLimit: 50}
+
+	tests := []struct {
+		name  string
+		query string
+		want  binding.Page
+	}{
+		{"defaults", "", binding.Page{Limit: 25, Offset: 0}},
+		{"limit and offset", "?limit=10&offset=30", binding.Page{Limit: 10, Offset: 30}},
+		{"page and per_page", "?page=3&per_page=10", binding.Page{Limit: 10, Offset: 20}},
+		{"page with default limit", "?page=2", binding.Page{Limit: 25, Offset: 25}},
+		{"clamped at max", "?limit=500", binding.Page{Limit: 50, Offset: 0}},
+		{"offset wins over page", "?offset=5&page=4", binding.Page{Limit: 25, Offset: 5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/items"+tt.query, nil)
+			got, err := binding.Pagination(req, defaults)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("want %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestPagination_PackageDefaults(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/items?limit=1000", nil)
+	got, err := binding.Pagination(req, binding.PageDefaults{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Limit != binding.DefaultMaxPageLimit {
+		t.Errorf("expected limit clamped to %d, got %d", binding.DefaultMaxPageLimit, got.Limit)
+	}
+}
+
+func TestPagination_InvalidInput(t *testing.T) {
+	for _, query := range []string{"?limit=abc", "?limit=-5", "?limit=0", "?offset=-1", "?page=0", "?page=x", "?page=9223372036854775807"} {
+		t.Run(query, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/items"+query, nil)
+			if _, err := binding.Pagination(req, binding.PageDefaults{}); err == nil {
+				t.Errorf("expected an error for %s", query)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package binding
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// BindQuery populates the struct dst points to from the request's query string.
+// Fields are matched by their `query:"name"` tag; untagged fields are left alone.
+// A `default:"..."` tag supplies the value when the key is absent (comma-separated for slices),
+// and `query:"name,required"` reports a missing key. Slice fields collect every value of a
+// repeated key, as in ?tag=a&tag=b.
+//
+// Supported field types are strings, bools, integers, floats and slices of them.
+// Missing required keys and unparsable values are reported together as ValidationErrors
+// keyed by query name; a dst that is not a pointer to a struct is a plain error.
+func BindQuery(req *http.Request, dst any) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("binding: BindQuery requires a pointer to a struct")
+	}
+	rv = rv.Elem()
+	rt := rv.Type()
+	query := req.URL.Query()
+
+	errs := ValidationErrors{}
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		tag, ok := f.Tag.Lookup("query")
+		if !ok || !f.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		values, present := query[name]
+		if !present {
+			if def, ok := f.Tag.Lookup("default"); ok {
+				values = []string{def}
+				if f.Type.Kind() == reflect.Slice {
+					values = strings.Split(def, ",")
+				}
+			} else if opts == "required" {
+				errs[name] = append(errs[name], "is required")
+				continue
+			} else {
+				continue
+			}
+		}
+
+		msg, err := bindQueryField(rv.Field(i), values)
+		if err != nil {
+			return fmt.Errorf("binding: field %s: %w", f.Name, err)
+		}
+		if msg != "" {
+			errs[name] = append(errs[name], msg)
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// bindQueryField parses values into field. A value that does not parse is reported as a
+// message for the caller to collect; an unsupported field type is an error.
+func bindQueryField(field reflect.Value, values []string) (string, error) {
+	if field.Kind() != reflect.Slice {
+		return parseQueryValue(field, values[len(values)-1])
+	}
+
+	slice := reflect.MakeSlice(field.Type(), len(values), len(values))
+	for i, v := range values {
+		if msg, err := parseQueryValue(slice.Index(i), v); msg != "" || err != nil {
+			return msg, err
+		}
+	}
+	field.Set(slice)
+	return "", nil
+}
+
+func parseQueryValue(v reflect.Value, s string) (string, error) {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return "must be a boolean", nil
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return "must be an integer", nil
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return "must be a non-negative integer", nil
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return "must be a number", nil
+		}
+		v.SetFloat(n)
+	default:
+		return "", fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return "", nil
+}
@@ -0,0 +1,75 @@
+package binding_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/elmq0022/kami/binding"
+)
+
+type search struct {
+	Q       string   `query:"q,required"`
+	Page    int      `query:"page" default:"1"`
+	Exact   bool     `query:"exact"`
+	Tags    []string `query:"tag"`
+	Sizes   []int    `query:"size" default:"10,20"`
+	Ignored string
+}
+
+func TestBindQuery_Defaults(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/search?q=kami", nil)
+
+	var got search
+	if err := binding.BindQuery(req, &got); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := search{Q: "kami", Page: 1, Sizes: []int{10, 20}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("want %+v, got %+v", want, got)
+	}
+}
+
+func TestBindQuery_ExplicitAndRepeatedKeys(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/search?q=kami&page=3&exact=true&tag=go&tag=web&size=5", nil)
+
+	var got search
+	if err := binding.BindQuery(req, &got); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := search{Q: "kami", Page: 3, Exact: true, Tags: []string{"go", "web"}, Sizes: []int{5}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("want %+v, got %+v", want, got)
+	}
+}
+
+func TestBindQuery_Errors(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/search?page=two&size=5&size=x", nil)
+
+	var got search
+	err := binding.BindQuery(req, &got)
+
+	var ve binding.ValidationErrors
+	if !errors.As(err, &ve) {
+		t.Fatalf("expected ValidationErrors, got %v", err)
+	}
+	want := binding.ValidationErrors{
+		"q":    {"is required"},
+		"page": {"must be an integer"},
+		"size": {"must be an integer"},
+	}
+	if !reflect.DeepEqual(ve, want) {
+		t.Fatalf("want %v, got %v", want, ve)
+	}
+}
+
+func TestBindQuery_RequiresStructPointer(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/search?q=kami", nil)
+
+	var got search
+	if err := binding.BindQuery(req, got); err == nil {
+		t.Fatal("expected an error for a non-pointer destination")
+	}
+}
@@ -0,0 +1,74 @@
+// Package binding provides helpers for reading typed data out of incoming requests,
+// such as multipart uploads.
+package binding
+
+import (
+	"errors"
+	"io"
+	"net/http"
+)
+
+// DefaultMaxUploadSize is the largest file StreamUpload accepts.
+const DefaultMaxUploadSize int64 = 32 << 20
+
+// ErrUploadTooLarge is returned when an uploaded file exceeds the allowed size.
+var ErrUploadTooLarge = errors.New("upload too large")
+
+// StreamUpload streams the file in the multipart field named field to the writer returned
+// by open, without buffering the file in memory. open receives the client-supplied file name
+// (reduced to its base name) and the writer is closed once the copy finishes.
+// Files larger than MultipartOptions.MaxFileSize, DefaultMaxUploadSize unless set, fail with
+// ErrUploadTooLarge. MaxMemory does not apply, as no part of the file is held in memory.
+// Only the first MultipartOptions value is used.
+// Returns http.ErrMissingFile if the field is not present.
+func StreamUpload(req *http.Request, field string, open func(name string) (io.WriteCloser, error), opts ...MultipartOptions) (int64, error) {
+	max := optionsFrom(opts).MaxFileSize
+	if max <= 0 {
+		max = DefaultMaxUploadSize
+	}
+	return StreamUploadN(req, field, max, open)
+}
+
+// StreamUploadN is like StreamUpload but rejects files larger than max bytes.
+// Bytes already written to the destination before the limit was hit are not removed;
+// callers should discard the destination when ErrUploadTooLarge is returned.
+func StreamUploadN(req *http.Request, field string, max int64, open func(name string) (io.WriteCloser, error)) (int64, error) {
+	mr, err := req.MultipartReader()
+	if err != nil {
+		return 0, err
+	}
+
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			return 0, http.ErrMissingFile
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		if part.FormName() != field || part.FileName() == "" {
+			part.Close()
+			continue
+		}
+
+		dst, err := open(part.FileName())
+		if err != nil {
+			part.Close()
+			return 0, err
+		}
+
+		n, err := io.Copy(dst, io.LimitReader(part, max+1))
+		part.Close()
+		if cerr := dst.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return n, err
+		}
+		if n > max {
+			return max, ErrUploadTooLarge
+		}
+		return n, nil
+	}
+}
@@ -0,0 +1,103 @@
+package binding_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/elmq0022/kami/binding"
+)
+
+type memoryFile struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (m *memoryFile) Close() error {
+	m.closed = true
+	return nil
+}
+
+func newUploadRequest(t *testing.T, field, filename, content string) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	mw.WriteField("title", "holiday")
+	fw, err := mw.CreateFormFile(field, filename)
+	if err != nil {
+		t.Fatalf("failed to create form file: %v", err)
+	}
+	io.WriteString(fw, content)
+	mw.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/upload", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	return req
+}
+
+func TestStreamUpload(t *testing.T) {
+	content := strings.Repeat("x", 4096)
+	req := newUploadRequest(t, "photo", "../../beach.jpg", content)
+
+	dst := &memoryFile{}
+	var gotName string
+	n, err := binding.StreamUpload(req, "photo", func(name string) (io.WriteCloser, error) {
+		gotName = name
+		return dst, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if n != int64(len(content)) {
+		t.Errorf("expected %d bytes, got %d", len(content), n)
+	}
+	if dst.String() != content {
+		t.Error("destination does not contain the uploaded bytes")
+	}
+	if !dst.closed {
+		t.Error("expected destination to be closed")
+	}
+	if gotName != "beach.jpg" {
+		t.Errorf("expected file name %q, got %q", "beach.jpg", gotName)
+	}
+}
+
+func TestStreamUploadN_TooLarge(t *testing.T) {
+	req := newUploadRequest(t, "photo", "big.bin", strings.Repeat("x", 100))
+
+	_, err := binding.StreamUploadN(req, "photo", 10, func(name string) (io.WriteCloser, error) {
+		return &memoryFile{}, nil
+	})
+	if !errors.Is(err, binding.ErrUploadTooLarge) {
+		t.Fatalf("expected ErrUploadTooLarge, got %v", err)
+	}
+}
+
+func TestStreamUpload_MaxFileSize(t *testing.T) {
+	req := newUploadRequest(t, "photo", "big.bin", strings.Repeat("x", 100))
+
+	_, err := binding.StreamUpload(req, "photo", func(name string) (io.WriteCloser, error) {
+		return &memoryFile{}, nil
+	}, binding.MultipartOptions{MaxFileSize: 10})
+	if !errors.Is(err, binding.ErrUploadTooLarge) {
+		t.Fatalf("expected ErrUploadTooLarge, got %v", err)
+	}
+}
+
+func TestStreamUpload_MissingField(t *testing.T) {
+	req := newUploadRequest(t, "photo", "beach.jpg", "data")
+
+	_, err := binding.StreamUpload(req, "avatar", func(name string) (io.WriteCloser, error) {
+		t.Fatal("open should not be called")
+		return nil, nil
+	})
+	if !errors.Is(err, http.ErrMissingFile) {
+		t.Fatalf("expected http.ErrMissingFile, got %v", err)
+	}
+}
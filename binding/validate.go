@@ -0,0 +1,96 @@
+package binding
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/elmq0022/kami/responders"
+)
+
+// Validator checks a decoded value. Implementations adapt a validation library to kami;
+// returning ValidationErrors lets callers report every field violation at once.
+type Validator interface {
+	Validate(v any) error
+}
+
+// ValidationErrors maps field names to the messages describing why they are invalid.
+// It is suitable for a 422 Unprocessable Entity response body.
+type ValidationErrors map[string][]string
+
+// Error lists the violations in field order.
+func (ve ValidationErrors) Error() string {
+	fields := make([]string, 0, len(ve))
+	for f := range ve {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+
+	parts := make([]string, 0, len(fields))
+	for _, f := range fields {
+		parts = append(parts, f+": "+strings.Join(ve[f], ", "))
+	}
+	return "validation failed: " + strings.Join(parts, "; ")
+}
+
+// RequiredValidator is the default Validator. It reports struct fields tagged
+// `validate:"required"` that hold their zero value, naming fields by their JSON name.
+type RequiredValidator struct{}
+
+// Validate checks the required fields of v, which must be a struct or a pointer to one.
+func (RequiredValidator) Validate(v any) error {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+
+	errs := ValidationErrors{}
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		for _, rule := range strings.Split(f.Tag.Get("validate"), ",") {
+			if strings.TrimSpace(rule) == "required" && rv.Field(i).IsZero() {
+				name := jsonFieldName(f)
+				errs[name] = append(errs[name], "is required")
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func jsonFieldName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return f.Name
+	}
+	return name
+}
+
+// BindAndValidate decodes the JSON request body into dst with the codec installed by
+// responders.SetJSONCodec, then checks it with validator, or RequiredValidator when validator is nil.
+// Decoding failures are wrapped; validation failures are whatever the validator returns,
+// typically ValidationErrors.
+func BindAndValidate(req *http.Request, dst any, validator Validator) error {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return fmt.Errorf("reading request body: %w", err)
+	}
+	if err := responders.UnmarshalJSON(body, dst); err != nil {
+		return fmt.Errorf("decoding request body: %w", err)
+	}
+
+	if validator == nil {
+		validator = RequiredValidator{}
+	}
+	return validator.Validate(dst)
+}
@@ -0,0 +1,74 @@
+package binding_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/elmq0022/kami/binding"
+)
+
+type signup struct {
+	Email string `json:"email" validate:"required"`
+	Name  string `json:"name" validate:"required"`
+	Bio   string `json:"bio"`
+}
+
+func TestBindAndValidate_Passing(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/signup", strings.NewReader(`{"email":"a@example.com","name":"Ada"}`))
+
+	var got signup
+	if err := binding.BindAndValidate(req, &got, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Email != "a@example.com" || got.Name != "Ada" {
+		t.Fatalf("unexpected decoded value %+v", got)
+	}
+}
+
+func TestBindAndValidate_MissingRequired(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/signup", strings.NewReader(`{"name":"Ada"}`))
+
+	var got signup
+	err := binding.BindAndValidate(req, &got, nil)
+
+	var ve binding.ValidationErrors
+	if !errors.As(err, &ve) {
+		t.Fatalf("expected ValidationErrors, got %v", err)
+	}
+	want := binding.ValidationErrors{"email": {"is required"}}
+	if !reflect.DeepEqual(ve, want) {
+		t.Fatalf("want %v, got %v", want, ve)
+	}
+}
+
+type rejectAll struct{}
+
+func (rejectAll) Validate(v any) error {
+	return binding.ValidationErrors{"bio": {"is not allowed"}}
+}
+
+func TestBindAndValidate_CustomValidator(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/signup", strings.NewReader(`{"email":"a@example.com","name":"Ada"}`))
+
+	var got signup
+	err := binding.BindAndValidate(req, &got, rejectAll{})
+	if err == nil || err.Error() != "validation failed: bio: is not allowed" {
+		t.Fatalf("expected custom validator error, got %v", err)
+	}
+}
+
+func TestBindAndValidate_InvalidJSON(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/signup", strings.NewReader(`{"email":`))
+
+	var got signup
+	err := binding.BindAndValidate(req, &got, nil)
+
+	var ve binding.ValidationErrors
+	if err == nil || errors.As(err, &ve) {
+		t.Fatalf("expected a decoding error, got %v", err)
+	}
+}
@@ -7,13 +7,16 @@ import (
 	"github.com/elmq0022/kami/types"
 )
 
-type defaultNotFoundResponder struct {
+// DefaultNotFoundResponder is the plain text Responder returned by DefaultNotFoundHandler.
+type DefaultNotFoundResponder struct {
 	status int
 	body   string
 }
 
+var _ types.Responder = (*DefaultNotFoundResponder)(nil)
+
 // Respond writes the plain text response to the ResponseWriter.
-func (dnf *defaultNotFoundResponder) Respond(w http.ResponseWriter, req *http.Request) {
+func (dnf *DefaultNotFoundResponder) Respond(w http.ResponseWriter, req *http.Request) {
 	w.WriteHeader(dnf.status)
 	w.Write([]byte(dnf.body))
 }
@@ -21,5 +24,5 @@ func (dnf *defaultNotFoundResponder) Respond(w http.ResponseWriter, req *http.Re
 // DefaultNotFoundHandler is the default 404 handler used by the router.
 // Returns a plain text "Not Found" response with HTTP 404 status.
 func DefaultNotFoundHandler(r *http.Request) types.Responder {
-	return &defaultNotFoundResponder{status: http.StatusNotFound, body: "Not Found"}
+	return &DefaultNotFoundResponder{status: http.StatusNotFound, body: "Not Found"}
 }
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/elmq0022/kami/handlers"
+	"github.com/elmq0022/kami/types"
 )
 
 func TestDefaultNotFoundHandler(t *testing.T) {
@@ -22,3 +23,12 @@ func TestDefaultNotFoundHandler(t *testing.T) {
 		t.Fatalf("want %s, got %s", "Not Found", rr.Body.String())
 	}
 }
+
+func TestDefaultNotFoundResponder_ImplementsResponder(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/foo", nil)
+
+	var responder types.Responder = handlers.DefaultNotFoundHandler(r)
+	if _, ok := responder.(*handlers.DefaultNotFoundResponder); !ok {
+		t.Fatalf("want *handlers.DefaultNotFoundResponder, got %T", responder)
+	}
+}
@@ -2,6 +2,7 @@ package radix
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/elmq0022/kami/types"
@@ -15,6 +16,7 @@ type Node struct {
 	wildcardName string
 	wildcard     *Node
 	terminal     map[string]types.Handler
+	pattern      string
 }
 
 type Radix struct {
@@ -26,27 +28,89 @@ func New() (*Radix, error) {
 	return &r, nil
 }
 
+// Clone returns a deep copy of the tree, sharing only the handlers, so routes can be
+// staged on the copy without affecting the original.
+func (r *Radix) Clone() *Radix {
+	return &Radix{root: r.root.clone()}
+}
+
+func (n *Node) clone() *Node {
+	if n == nil {
+		return nil
+	}
+	c := *n
+	c.param = n.param.clone()
+	c.wildcard = n.wildcard.clone()
+	if n.children != nil {
+		c.children = make([]*Node, len(n.children))
+		for i, child := range n.children {
+			c.children[i] = child.clone()
+		}
+	}
+	if n.terminal != nil {
+		c.terminal = make(map[string]types.Handler, len(n.terminal))
+		for m, h := range n.terminal {
+			c.terminal[m] = h
+		}
+	}
+	return &c
+}
+
+// AddRoute registers handler for method at path. A trailing param may be marked optional
+// with a '?' suffix, as in "/posts/:year/:month?", in which case the handler is registered
+// both with and without the final segment.
 func (r *Radix) AddRoute(method string, path string, handler types.Handler) error {
 	if len(path) == 0 || path[0] != '/' {
 		return fmt.Errorf("path must start with '/'")
 	}
 
-	route := types.Route{Method: method, Path: path, Handler: handler}
 	segments := pathSegments(path)
 
+	if err := validateSegments(path, segments); err != nil {
+		return err
+	}
 	if err := validate_NoDuplicateParams(path, segments); err != nil {
 		return err
 	}
 
+	pattern := "/" + strings.Join(segments, "/")
+	route := types.Route{Method: method, Path: pattern, Handler: handler}
+
+	if required, ok := optionalSegments(segments); ok {
+		// Insert both forms into a copy so a failure on the second leaves the tree untouched
+		root := r.root.clone()
+		if err := r.insert(route, root, required[:len(required)-1], 0); err != nil {
+			return err
+		}
+		if err := r.insert(route, root, required, 0); err != nil {
+			return err
+		}
+		r.root = root
+		return nil
+	}
+
 	return r.insert(route, r.root, segments, 0)
 }
 
+// optionalSegments reports whether the last segment is an optional param, returning the
+// segments with its '?' suffix removed.
+func optionalSegments(segments []string) ([]string, bool) {
+	last := len(segments) - 1
+	if last < 0 || segments[last][0] != ':' || !strings.HasSuffix(segments[last], "?") {
+		return segments, false
+	}
+	required := append([]string{}, segments...)
+	required[last] = strings.TrimSuffix(required[last], "?")
+	return required, true
+}
+
 func (r *Radix) insert(route types.Route, node *Node, segments []string, pos int) error {
 	if pos >= len(segments) {
 		if node.terminal == nil {
 			node.terminal = make(map[string]types.Handler)
 		}
 		node.terminal[route.Method] = route.Handler
+		node.pattern = route.Path
 		return nil
 	}
 
@@ -74,6 +138,8 @@ func (r *Radix) insert(route types.Route, node *Node, segments []string, pos int
 		if node.wildcard == nil {
 			node.wildcard = &Node{wildcardName: seg[1:]}
 			return r.insert(route, node.wildcard, segments, pos+1)
+		} else if node.wildcard.wildcardName == seg[1:] {
+			return r.insert(route, node.wildcard, segments, pos+1)
 		}
 		return fmt.Errorf("multiple wildcards at same node for path '%s'", route.Path)
 	}
@@ -89,57 +155,206 @@ func (r *Radix) insert(route types.Route, node *Node, segments []string, pos int
 	return r.insert(route, n, segments, pos+1)
 }
 
+// Lookup finds the handler registered for method at path along with the captured params.
+// An empty path is treated as "/", so requests without a path match the root route.
 func (r *Radix) Lookup(method, path string) (types.Handler, map[string]string, bool) {
-	root := r.root
-	segments := pathSegments(path)
-	params := make(map[string]string)
-	handler, ok := lookup(root, method, segments, 0, params)
+	handler, params, _, ok := r.Match(method, path)
 	return handler, params, ok
 }
 
-func lookup(node *Node, method string, segments []string, pos int, params map[string]string) (types.Handler, bool) {
-	var zero types.Handler
+// Match is like Lookup but also returns the pattern of the matched route, such as "/users/:id".
+// The params map is only allocated once a param or wildcard is captured, so it is nil for
+// routes without any; reading from a nil map is safe.
+func (r *Radix) Match(method, path string) (types.Handler, map[string]string, string, bool) {
+	if path == "" {
+		path = "/"
+	}
+	segments := pathSegments(path)
+	var params map[string]string
+	node := lookup(r.root, method, segments, 0, &params)
+	if node == nil {
+		return nil, params, "", false
+	}
+	return node.terminal[method], params, node.pattern, true
+}
 
+// lookup returns the node holding the handler for method, or nil if there is none.
+func lookup(node *Node, method string, segments []string, pos int, params *map[string]string) *Node {
 	if node == nil {
-		return zero, false
+		return nil
 	}
 
 	if pos >= len(segments) {
 		// Check for terminal handler at this node
-		if handler, ok := node.terminal[method]; ok {
-			return handler, ok
+		if _, ok := node.terminal[method]; ok {
+			return node
 		}
 
 		// Allow wildcard to match empty string
 		if node.wildcard != nil {
-			params[node.wildcard.wildcardName] = ""
-			h, ok := node.wildcard.terminal[method]
-			return h, ok
+			setParam(params, node.wildcard.wildcardName, "")
+			return terminalFor(node.wildcard, method)
 		}
 
-		return zero, false
+		return nil
 	}
 
 	for _, child := range node.children {
 		if segments[pos] == child.prefix {
-			h, ok := lookup(child, method, segments, pos+1, params)
-			return h, ok
+			return lookup(child, method, segments, pos+1, params)
 		}
 	}
 
 	if node.param != nil {
-		params[node.param.paramName] = segments[pos]
-		h, ok := lookup(node.param, method, segments, pos+1, params)
-		return h, ok
+		setParam(params, node.param.paramName, segments[pos])
+		return lookup(node.param, method, segments, pos+1, params)
+	}
+
+	if node.wildcard != nil {
+		setParam(params, node.wildcard.wildcardName, strings.Join(segments[pos:], "/"))
+		return terminalFor(node.wildcard, method)
+	}
+
+	return nil
+}
+
+// setParam stores a captured value, allocating the params map on first use.
+func setParam(params *map[string]string, name, value string) {
+	if *params == nil {
+		*params = make(map[string]string)
+	}
+	(*params)[name] = value
+}
+
+func terminalFor(node *Node, method string) *Node {
+	if _, ok := node.terminal[method]; ok {
+		return node
+	}
+	return nil
+}
+
+// Has reports whether a handler is registered for method at exactly the given pattern,
+// as opposed to Lookup, which matches a concrete request path. For a pattern ending in an
+// optional param it reports whether either the short or the full form is registered.
+func (r *Radix) Has(method, pattern string) bool {
+	segments := pathSegments(pattern)
+	if required, ok := optionalSegments(segments); ok {
+		return r.has(method, required[:len(required)-1]) || r.has(method, required)
 	}
+	return r.has(method, segments)
+}
+
+func (r *Radix) has(method string, segments []string) bool {
+	node := r.root
+	for _, seg := range segments {
+		switch seg[0] {
+		case ':':
+			if node.param == nil || node.param.paramName != seg[1:] {
+				return false
+			}
+			node = node.param
+		case '*':
+			if node.wildcard == nil || node.wildcard.wildcardName != seg[1:] {
+				return false
+			}
+			node = node.wildcard
+		default:
+			var next *Node
+			for _, child := range node.children {
+				if child.prefix == seg {
+					next = child
+					break
+				}
+			}
+			if next == nil {
+				return false
+			}
+			node = next
+		}
+	}
+	_, ok := node.terminal[method]
+	return ok
+}
 
+// Routes returns every registered route, with paths rebuilt in the router's pattern syntax.
+// Routes are ordered by path and then by method.
+func (r *Radix) Routes() types.Routes {
+	var routes types.Routes
+	collect(r.root, "", &routes)
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+	return routes
+}
+
+func collect(node *Node, path string, routes *types.Routes) {
+	for method, handler := range node.terminal {
+		p := path
+		if p == "" {
+			p = "/"
+		}
+		*routes = append(*routes, types.Route{Method: method, Path: p, Handler: handler})
+	}
+
+	for _, child := range node.children {
+		collect(child, path+"/"+child.prefix, routes)
+	}
+	if node.param != nil {
+		collect(node.param, path+"/:"+node.param.paramName, routes)
+	}
 	if node.wildcard != nil {
-		params[node.wildcard.wildcardName] = strings.Join(segments[pos:], "/")
-		h, ok := node.wildcard.terminal[method]
-		return h, ok
+		collect(node.wildcard, path+"/*"+node.wildcard.wildcardName, routes)
 	}
+}
+
+// Methods returns the sorted set of methods registered for the node that path
+// resolves to, regardless of the request method. It is empty when no route
+// exists for the path at all.
+func (r *Radix) Methods(path string) []string {
+	node := r.root
+	segments := pathSegments(path)
+	set := make(map[string]bool)
 
-	return zero, false
+	for pos := 0; node != nil; pos++ {
+		if pos >= len(segments) {
+			for m := range node.terminal {
+				set[m] = true
+			}
+			if node.wildcard != nil {
+				for m := range node.wildcard.terminal {
+					set[m] = true
+				}
+			}
+			break
+		}
+
+		var next *Node
+		for _, child := range node.children {
+			if segments[pos] == child.prefix {
+				next = child
+				break
+			}
+		}
+		if next == nil && node.param != nil {
+			next = node.param
+		}
+		if next == nil && node.wildcard != nil {
+			for m := range node.wildcard.terminal {
+				set[m] = true
+			}
+		}
+		node = next
+	}
+
+	methods := make([]string, 0, len(set))
+	for m := range set {
+		methods = append(methods, m)
+	}
+	sort.Strings(methods)
+	return methods
 }
 
 func pathSegments(path string) []string {
@@ -155,14 +370,44 @@ func pathSegments(path string) []string {
 	return segments[:p]
 }
 
+// validateSegments checks the param and wildcard syntax of every segment in path:
+// names must be non-empty and free of ':' and '*', a wildcard may only be the last segment,
+// and only the last segment may be an optional param.
+func validateSegments(path string, segments []string) error {
+	for i, seg := range segments {
+		if seg[0] != ':' && seg[0] != '*' {
+			continue
+		}
+
+		name := seg[1:]
+		if seg[0] == ':' && strings.HasSuffix(name, "?") {
+			if i != len(segments)-1 {
+				return fmt.Errorf("invalid segment %q in %s: only the last param may be optional", seg, path)
+			}
+			name = strings.TrimSuffix(name, "?")
+		}
+
+		switch {
+		case name == "":
+			return fmt.Errorf("invalid segment %q in %s: missing name", seg, path)
+		case strings.ContainsAny(name, ":*"):
+			return fmt.Errorf("invalid segment %q in %s: name must not contain ':' or '*'", seg, path)
+		case seg[0] == '*' && i != len(segments)-1:
+			return fmt.Errorf("invalid segment %q in %s: wildcard must be the last segment", seg, path)
+		}
+	}
+	return nil
+}
+
 func validate_NoDuplicateParams(path string, segments []string) error {
 	seen := make(map[string]bool)
 	for _, seg := range segments {
 		if len(seg) >= 1 && (seg[0] == ':' || seg[0] == '*') {
-			if _, ok := seen[seg[1:]]; ok {
-				return fmt.Errorf("duplicate parameter %s defined in path %s", seg[1:], path)
+			name := strings.TrimSuffix(seg[1:], "?")
+			if _, ok := seen[name]; ok {
+				return fmt.Errorf("duplicate parameter %s defined in path %s", name, path)
 			}
-			seen[seg[1:]] = true
+			seen[name] = true
 		}
 	}
 	return nil
@@ -1,7 +1,10 @@
 package radix_test
 
 import (
+	"maps"
 	"net/http"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/elmq0022/kami/internal/radix"
@@ -246,6 +249,18 @@ func TestRadix_Lookup(t *testing.T) {
 			wantFound:  true,
 		},
 
+		// Empty path
+		{
+			name: "empty path matches root",
+			routes: types.Routes{
+				{Path: "/", Method: http.MethodGet, Handler: MakeTestHandler("root")},
+			},
+			method:    http.MethodGet,
+			path:      "",
+			wantValue: "root",
+			wantFound: true,
+		},
+
 		// Method mismatch
 		{
 			name: "wrong method",
@@ -301,3 +316,273 @@ func TestRadix_Lookup(t *testing.T) {
 		})
 	}
 }
+
+func TestRadix_Methods(t *testing.T) {
+	r, _ := radix.New()
+	r.AddRoute(http.MethodGet, "/user/:id", MakeTestHandler("get"))
+	r.AddRoute(http.MethodDelete, "/user/:id", MakeTestHandler("delete"))
+	r.AddRoute(http.MethodGet, "/static/*path", MakeTestHandler("static"))
+
+	tests := []struct {
+		name string
+		path string
+		want []string
+	}{
+		{name: "param route", path: "/user/alice", want: []string{http.MethodDelete, http.MethodGet}},
+		{name: "wildcard route", path: "/static/js/app.js", want: []string{http.MethodGet}},
+		{name: "wildcard empty match", path: "/static", want: []string{http.MethodGet}},
+		{name: "unknown path", path: "/missing", want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := r.Methods(tt.path)
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("want %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRadix_Routes(t *testing.T) {
+	r, _ := radix.New()
+	r.AddRoute(http.MethodGet, "/", MakeTestHandler("root"))
+	r.AddRoute(http.MethodPost, "/user/:id", MakeTestHandler("post"))
+	r.AddRoute(http.MethodGet, "/user/:id", MakeTestHandler("get"))
+	r.AddRoute(http.MethodGet, "/static/*path", MakeTestHandler("static"))
+
+	want := []string{
+		"GET /",
+		"GET /static/*path",
+		"GET /user/:id",
+		"POST /user/:id",
+	}
+
+	var got []string
+	for _, route := range r.Routes() {
+		got = append(got, route.Method+" "+route.Path)
+	}
+
+	if !slices.Equal(got, want) {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+}
+
+func TestRadix_MatchPattern(t *testing.T) {
+	r, _ := radix.New()
+	r.AddRoute(http.MethodGet, "/", MakeTestHandler("root"))
+	r.AddRoute(http.MethodGet, "/user/:id", MakeTestHandler("user"))
+	r.AddRoute(http.MethodGet, "/user/list", MakeTestHandler("list"))
+	r.AddRoute(http.MethodGet, "/static/*path", MakeTestHandler("static"))
+
+	tests := []struct {
+		name      string
+		path      string
+		want      string
+		wantFound bool
+	}{
+		{name: "root", path: "/", want: "/", wantFound: true},
+		{name: "param", path: "/user/42", want: "/user/:id", wantFound: true},
+		{name: "static wins", path: "/user/list", want: "/user/list", wantFound: true},
+		{name: "wildcard", path: "/static/js/app.js", want: "/static/*path", wantFound: true},
+		{name: "wildcard empty match", path: "/static", want: "/static/*path", wantFound: true},
+		{name: "miss", path: "/missing", want: "", wantFound: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, got, found := r.Match(http.MethodGet, tt.path)
+			if found != tt.wantFound {
+				t.Fatalf("expected found=%v, got %v", tt.wantFound, found)
+			}
+			if got != tt.want {
+				t.Fatalf("expected pattern %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRadix_SegmentValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		wantErr string
+	}{
+		{name: "empty param name", path: "/foo/:", wantErr: `invalid segment ":" in /foo/:`},
+		{name: "empty param name mid path", path: "/foo/:/bar", wantErr: `invalid segment ":" in /foo/:/bar`},
+		{name: "extra colon in param", path: "/foo/:id:name", wantErr: `invalid segment ":id:name" in /foo/:id:name`},
+		{name: "star in param", path: "/foo/:id*", wantErr: `invalid segment ":id*" in /foo/:id*`},
+		{name: "empty wildcard name", path: "/files/*", wantErr: `invalid segment "*" in /files/*`},
+		{name: "double wildcard", path: "/files/*a/*b", wantErr: `invalid segment "*a" in /files/*a/*b`},
+		{name: "valid param", path: "/users/:id"},
+		{name: "valid wildcard", path: "/files/*path"},
+		{name: "valid mixed", path: "/users/:id/files/*path"},
+		{name: "colon inside static segment", path: "/time/12:30"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := radix.New()
+			err := r.AddRoute(http.MethodGet, tt.path, MakeTestHandler("test"))
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("expected no error for %q, got %v", tt.path, err)
+				}
+				return
+			}
+			if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error starting with %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestRadix_OptionalTrailingParam(t *testing.T) {
+	r, _ := radix.New()
+	if err := r.AddRoute(http.MethodGet, "/posts/:year/:month?", MakeTestHandler("posts")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		wantParams map[string]string
+	}{
+		{name: "without optional", path: "/posts/2024", wantParams: map[string]string{"year": "2024"}},
+		{name: "with optional", path: "/posts/2024/06", wantParams: map[string]string{"year": "2024", "month": "06"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, params, pattern, found := r.Match(http.MethodGet, tt.path)
+			if !found {
+				t.Fatalf("expected %s to match", tt.path)
+			}
+			if got := ReadTestHandler(h); got != "posts" {
+				t.Fatalf("expected handler posts, got %v", got)
+			}
+			if !maps.Equal(params, tt.wantParams) {
+				t.Fatalf("want params %v, got %v", tt.wantParams, params)
+			}
+			if pattern != "/posts/:year/:month?" {
+				t.Fatalf("expected pattern %q, got %q", "/posts/:year/:month?", pattern)
+			}
+		})
+	}
+
+	if _, _, found := r.Lookup(http.MethodGet, "/posts/2024/06/01"); found {
+		t.Fatal("expected extra segments not to match")
+	}
+}
+
+func TestRadix_OptionalParamValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+	}{
+		{name: "optional param not last", path: "/posts/:year?/:month"},
+		{name: "duplicate optional name", path: "/posts/:id/:id?"},
+		{name: "empty optional name", path: "/posts/:?"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := radix.New()
+			if err := r.AddRoute(http.MethodGet, tt.path, MakeTestHandler("test")); err == nil {
+				t.Fatalf("expected error for %q", tt.path)
+			}
+		})
+	}
+}
+
+func TestRadix_Has(t *testing.T) {
+	r, _ := radix.New()
+	r.AddRoute(http.MethodGet, "/user/:id", MakeTestHandler("user"))
+	r.AddRoute(http.MethodGet, "/static/*path", MakeTestHandler("static"))
+	r.AddRoute(http.MethodGet, "/posts/:year/:month?", MakeTestHandler("posts"))
+	r.AddRoute(http.MethodGet, "/tags/:tag", MakeTestHandler("tag"))
+
+	tests := []struct {
+		method  string
+		pattern string
+		want    bool
+	}{
+		{method: http.MethodGet, pattern: "/posts/:year/:month?", want: true},
+		{method: http.MethodGet, pattern: "/posts/:year/:month", want: true},
+		{method: http.MethodGet, pattern: "/tags/:tag/:page?", want: true},
+		{method: http.MethodPost, pattern: "/posts/:year/:month?", want: false},
+		{method: http.MethodGet, pattern: "/user/:id", want: true},
+		{method: http.MethodPost, pattern: "/user/:id", want: false},
+		{method: http.MethodGet, pattern: "/user/:name", want: false},
+		{method: http.MethodGet, pattern: "/user/42", want: false},
+		{method: http.MethodGet, pattern: "/static/*path", want: true},
+		{method: http.MethodGet, pattern: "/missing", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.pattern, func(t *testing.T) {
+			if got := r.Has(tt.method, tt.pattern); got != tt.want {
+				t.Fatalf("want %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRadix_OptionalParamFailureLeavesTreeUnchanged(t *testing.T) {
+	r, _ := radix.New()
+	r.AddRoute(http.MethodGet, "/posts/:year/:slug", MakeTestHandler("post"))
+
+	if err := r.AddRoute(http.MethodPost, "/posts/:year/:month?", MakeTestHandler("archive")); err == nil {
+		t.Fatal("expected a parameter name conflict")
+	}
+	if _, _, ok := r.Lookup(http.MethodPost, "/posts/2024"); ok {
+		t.Fatal("expected the short form not to be registered after a failed insert")
+	}
+	if _, _, ok := r.Lookup(http.MethodGet, "/posts/2024/hello"); !ok {
+		t.Fatal("expected existing routes to be kept")
+	}
+}
+
+func TestRadix_WildcardMultipleMethods(t *testing.T) {
+	r, _ := radix.New()
+	if err := r.AddRoute(http.MethodGet, "/files/*path", MakeTestHandler("get")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := r.AddRoute(http.MethodPut, "/files/*path", MakeTestHandler("put")); err != nil {
+		t.Fatalf("expected a second method on the same wildcard to succeed, got %v", err)
+	}
+	if err := r.AddRoute(http.MethodPost, "/files/*other", MakeTestHandler("post")); err == nil {
+		t.Fatal("expected a differently named wildcard at the same node to fail")
+	}
+
+	h, params, found := r.Lookup(http.MethodPut, "/files/a/b")
+	if !found || ReadTestHandler(h) != "put" || params["path"] != "a/b" {
+		t.Fatalf("expected put handler with path a/b, got found=%v params=%v", found, params)
+	}
+}
+
+func TestRadix_CloneIsIndependent(t *testing.T) {
+	r, _ := radix.New()
+	if err := r.AddRoute(http.MethodGet, "/users/:id", MakeTestHandler("user")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	c := r.Clone()
+	if err := c.AddRoute(http.MethodPost, "/users/:id", MakeTestHandler("update")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.AddRoute(http.MethodGet, "/posts", MakeTestHandler("posts")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := len(r.Routes()); got != 1 {
+		t.Errorf("expected original to keep 1 route, got %d", got)
+	}
+	if got := len(c.Routes()); got != 3 {
+		t.Errorf("expected clone to have 3 routes, got %d", got)
+	}
+	if h, _, found := c.Lookup(http.MethodGet, "/users/7"); !found || ReadTestHandler(h) != "user" {
+		t.Errorf("expected clone to keep the original route")
+	}
+}
@@ -0,0 +1,50 @@
+// Package kamitest provides helpers for exercising routers and handlers in tests
+// without the httptest request and recorder boilerplate.
+package kamitest
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+)
+
+// Response is the recorded result of a request made with Do.
+type Response struct {
+	Code   int
+	Body   string
+	Header http.Header
+}
+
+// JSON decodes the response body into dst.
+func (r *Response) JSON(dst any) error {
+	return json.Unmarshal([]byte(r.Body), dst)
+}
+
+// Option modifies the request built by Do before it is served.
+type Option func(*http.Request)
+
+// WithHeader sets a request header.
+func WithHeader(key, value string) Option {
+	return func(req *http.Request) {
+		req.Header.Set(key, value)
+	}
+}
+
+// Do serves a request with the given method, path, and body through h and returns the recorded response.
+// The body may be nil.
+func Do(h http.Handler, method, path string, body io.Reader, opts ...Option) *Response {
+	req := httptest.NewRequest(method, path, body)
+	for _, opt := range opts {
+		opt(req)
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	return &Response{
+		Code:   w.Code,
+		Body:   w.Body.String(),
+		Header: w.Result().Header,
+	}
+}
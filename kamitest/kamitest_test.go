@@ -0,0 +1,88 @@
+package kamitest_test
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/elmq0022/kami/kamitest"
+	"github.com/elmq0022/kami/responders"
+	"github.com/elmq0022/kami/router"
+	"github.com/elmq0022/kami/types"
+)
+
+func newTestRouter(t *testing.T) *router.Router {
+	t.Helper()
+	r, err := router.New()
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+
+	r.Prefix("/users/:id").GET(func(req *http.Request) types.Responder {
+		params := router.GetParams(req.Context())
+		return responders.JSONResponse(map[string]string{
+			"id":    params["id"],
+			"token": req.Header.Get("X-Token"),
+		}, http.StatusOK)
+	})
+	r.Prefix("/echo").POST(func(req *http.Request) types.Responder {
+		body, _ := io.ReadAll(req.Body)
+		return responders.ReaderResponse(strings.NewReader(string(body)), "text/plain", http.StatusCreated)
+	})
+	return r
+}
+
+func TestDo_StatusAndBody(t *testing.T) {
+	r := newTestRouter(t)
+
+	res := kamitest.Do(r, http.MethodPost, "/echo", strings.NewReader("ping"))
+
+	if res.Code != http.StatusCreated {
+		t.Errorf("expected status %d, got %d", http.StatusCreated, res.Code)
+	}
+	if res.Body != "ping" {
+		t.Errorf("expected body %q, got %q", "ping", res.Body)
+	}
+	if ct := res.Header.Get("Content-Type"); ct != "text/plain" {
+		t.Errorf("expected Content-Type text/plain, got %q", ct)
+	}
+}
+
+func TestDo_NotFound(t *testing.T) {
+	r := newTestRouter(t)
+
+	res := kamitest.Do(r, http.MethodGet, "/missing", nil)
+
+	if res.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, res.Code)
+	}
+}
+
+func TestResponse_JSON(t *testing.T) {
+	r := newTestRouter(t)
+
+	res := kamitest.Do(r, http.MethodGet, "/users/42", nil, kamitest.WithHeader("X-Token", "secret"))
+
+	var got map[string]string
+	if err := res.JSON(&got); err != nil {
+		t.Fatalf("failed to decode JSON: %v", err)
+	}
+	if got["id"] != "42" {
+		t.Errorf("expected id 42, got %q", got["id"])
+	}
+	if got["token"] != "secret" {
+		t.Errorf("expected header to reach the handler, got %q", got["token"])
+	}
+}
+
+func TestResponse_JSON_Invalid(t *testing.T) {
+	r := newTestRouter(t)
+
+	res := kamitest.Do(r, http.MethodPost, "/echo", strings.NewReader("not json"))
+
+	var got map[string]string
+	if err := res.JSON(&got); err == nil {
+		t.Error("expected error decoding non-JSON body")
+	}
+}
@@ -0,0 +1,44 @@
+package responders
+
+import (
+	"encoding/json"
+	"sync/atomic"
+)
+
+type jsonCodec struct {
+	marshal   func(any) ([]byte, error)
+	unmarshal func([]byte, any) error
+	std       bool
+}
+
+var codec atomic.Pointer[jsonCodec]
+
+func init() {
+	codec.Store(&jsonCodec{marshal: json.Marshal, unmarshal: json.Unmarshal, std: true})
+}
+
+// SetJSONCodec replaces the functions used by kami's JSON responders and the binding package,
+// so a faster library such as jsoniter or sonic can stand in for encoding/json.
+// Passing nil for either function restores the encoding/json default for it.
+// It is safe to call concurrently with requests, but is intended to be called once during initialization.
+func SetJSONCodec(marshal func(any) ([]byte, error), unmarshal func([]byte, any) error) {
+	c := &jsonCodec{marshal: marshal, unmarshal: unmarshal}
+	if c.marshal == nil {
+		c.marshal = json.Marshal
+	}
+	if c.unmarshal == nil {
+		c.unmarshal = json.Unmarshal
+	}
+	c.std = marshal == nil && unmarshal == nil
+	codec.Store(c)
+}
+
+// MarshalJSON encodes v with the codec installed by SetJSONCodec.
+func MarshalJSON(v any) ([]byte, error) {
+	return codec.Load().marshal(v)
+}
+
+// UnmarshalJSON decodes data into v with the codec installed by SetJSONCodec.
+func UnmarshalJSON(data []byte, v any) error {
+	return codec.Load().unmarshal(data, v)
+}
@@ -0,0 +1,72 @@
+package responders_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/elmq0022/kami/responders"
+)
+
+func TestSetJSONCodec(t *testing.T) {
+	t.Cleanup(func() { responders.SetJSONCodec(nil, nil) })
+
+	marshalCalls, unmarshalCalls := 0, 0
+	responders.SetJSONCodec(
+		func(v any) ([]byte, error) {
+			marshalCalls++
+			return []byte(`{"codec":"custom"}`), nil
+		},
+		func(data []byte, v any) error {
+			unmarshalCalls++
+			return json.Unmarshal(data, v)
+		},
+	)
+
+	tests := []struct {
+		name      string
+		responder interface {
+			Respond(http.ResponseWriter, *http.Request)
+		}
+		want string
+	}{
+		{name: "json", responder: responders.JSONResponse(map[string]int{"a": 1}, http.StatusOK), want: `{"codec":"custom"}`},
+		{name: "json error", responder: responders.JSONErrorResponse("bad", http.StatusBadRequest), want: `{"codec":"custom"}`},
+		{name: "json stream", responder: responders.JSONStreamResponse([]int{1, 2}, http.StatusOK), want: "{\"codec\":\"custom\"}\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			tt.responder.Respond(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			if w.Body.String() != tt.want {
+				t.Fatalf("expected custom codec output %q, got %q", tt.want, w.Body.String())
+			}
+		})
+	}
+	if marshalCalls != len(tests) {
+		t.Fatalf("expected %d marshal calls, got %d", len(tests), marshalCalls)
+	}
+
+	var got map[string]int
+	if err := responders.UnmarshalJSON([]byte(`{"a":1}`), &got); err != nil || got["a"] != 1 {
+		t.Fatalf("unexpected unmarshal result %v, %v", got, err)
+	}
+	if unmarshalCalls != 1 {
+		t.Fatalf("expected 1 unmarshal call, got %d", unmarshalCalls)
+	}
+}
+
+func TestSetJSONCodec_NilRestoresDefault(t *testing.T) {
+	responders.SetJSONCodec(func(any) ([]byte, error) { return []byte("custom"), nil }, nil)
+	responders.SetJSONCodec(nil, nil)
+
+	w := httptest.NewRecorder()
+	responders.JSONResponse(map[string]int{"a": 1}, http.StatusOK).Respond(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if w.Body.String() != `{"a":1}` {
+		t.Fatalf("expected encoding/json output, got %q", w.Body.String())
+	}
+}
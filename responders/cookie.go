@@ -0,0 +1,27 @@
+package responders
+
+import (
+	"net/http"
+
+	"github.com/elmq0022/kami/types"
+)
+
+type cookieResponder struct {
+	inner   types.Responder
+	cookies []*http.Cookie
+}
+
+// WithCookie wraps inner so its response sets the given cookies, letting a handler return a
+// body or redirect and set a cookie in one go. Cookies are written with http.SetCookie, so
+// their attributes pass through unchanged; invalid cookies are dropped by the http package.
+func WithCookie(inner types.Responder, cookies ...*http.Cookie) types.Responder {
+	return &cookieResponder{inner: inner, cookies: cookies}
+}
+
+// Respond adds a Set-Cookie header per cookie and delegates to the wrapped responder.
+func (c *cookieResponder) Respond(w http.ResponseWriter, req *http.Request) {
+	for _, cookie := range c.cookies {
+		http.SetCookie(w, cookie)
+	}
+	c.inner.Respond(w, req)
+}
@@ -0,0 +1,30 @@
+package responders_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/elmq0022/kami/responders"
+)
+
+func TestWithCookie(t *testing.T) {
+	session := &http.Cookie{Name: "session", Value: "abc123", Path: "/", HttpOnly: true, Secure: true, SameSite: http.SameSiteLaxMode}
+	theme := &http.Cookie{Name: "theme", Value: "dark", MaxAge: 3600}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	responders.WithCookie(responders.JSONResponse(map[string]string{"status": "ok"}, http.StatusCreated), session, theme).Respond(w, r)
+
+	got := w.Header().Values("Set-Cookie")
+	want := []string{session.String(), theme.String()}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("expected Set-Cookie %q, got %q", want, got)
+	}
+	if w.Code != http.StatusCreated {
+		t.Errorf("expected status %d, got %d", http.StatusCreated, w.Code)
+	}
+	if w.Body.String() != `{"status":"ok"}` {
+		t.Errorf("expected inner body, got %q", w.Body.String())
+	}
+}
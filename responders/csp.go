@@ -0,0 +1,25 @@
+package responders
+
+import (
+	"net/http"
+
+	"github.com/elmq0022/kami/types"
+)
+
+type cspReportOnlyResponder struct {
+	inner  types.Responder
+	policy string
+}
+
+// WithCSPReportOnly wraps inner so its response carries a Content-Security-Policy-Report-Only
+// header with the given policy. Browsers report violations of the policy without enforcing it,
+// which makes it useful for trialling a policy before switching to Content-Security-Policy.
+func WithCSPReportOnly(inner types.Responder, policy string) types.Responder {
+	return &cspReportOnlyResponder{inner: inner, policy: policy}
+}
+
+// Respond sets the report-only header and delegates to the wrapped responder.
+func (c *cspReportOnlyResponder) Respond(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Security-Policy-Report-Only", c.policy)
+	c.inner.Respond(w, req)
+}
@@ -0,0 +1,30 @@
+package responders_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/elmq0022/kami/responders"
+)
+
+func TestWithCSPReportOnly(t *testing.T) {
+	policy := "default-src 'self'; report-uri /csp-report"
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	responders.WithCSPReportOnly(responders.JSONResponse("ok", http.StatusOK), policy).Respond(w, r)
+
+	if got := w.Header().Get("Content-Security-Policy-Report-Only"); got != policy {
+		t.Errorf("expected report-only policy %q, got %q", policy, got)
+	}
+	if got := w.Header().Get("Content-Security-Policy"); got != "" {
+		t.Errorf("expected no enforcing policy, got %q", got)
+	}
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if w.Body.String() != `"ok"` {
+		t.Errorf("expected inner body, got %q", w.Body.String())
+	}
+}
@@ -0,0 +1,39 @@
+package responders
+
+import (
+	"errors"
+	"net/http"
+)
+
+// StatusCoder is implemented by errors that know which HTTP status they map to.
+type StatusCoder interface {
+	StatusCode() int
+}
+
+// PublicMessager is implemented by errors that carry a message safe to show to clients.
+type PublicMessager interface {
+	PublicMessage() string
+}
+
+// ErrorResponse creates a JSON error responder for a domain error. The status comes from a
+// StatusCoder and the message from a PublicMessager anywhere in err's chain, as found by errors.As.
+// Without a StatusCoder the status is 500; without a PublicMessager the message is the status
+// text, or "internal error" for a 500, so err.Error() itself is never sent to the client.
+func ErrorResponse(err error) *jsonErrorResponder {
+	status := http.StatusInternalServerError
+	var sc StatusCoder
+	if errors.As(err, &sc) {
+		status = sc.StatusCode()
+	}
+
+	msg := http.StatusText(status)
+	if status == http.StatusInternalServerError {
+		msg = "internal error"
+	}
+	var pm PublicMessager
+	if errors.As(err, &pm) {
+		msg = pm.PublicMessage()
+	}
+
+	return JSONErrorResponse(msg, status)
+}
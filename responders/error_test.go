@@ -0,0 +1,76 @@
+package responders_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/elmq0022/kami/responders"
+)
+
+type notFoundError struct {
+	id string
+}
+
+func (e *notFoundError) Error() string         { return "sql: no rows for user " + e.id }
+func (e *notFoundError) StatusCode() int       { return http.StatusNotFound }
+func (e *notFoundError) PublicMessage() string { return "user not found" }
+
+type conflictError struct{}
+
+func (conflictError) Error() string   { return "unique constraint violated on users.email" }
+func (conflictError) StatusCode() int { return http.StatusConflict }
+
+func TestErrorResponse(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "status and public message",
+			err:        &notFoundError{id: "42"},
+			wantStatus: http.StatusNotFound,
+			wantBody:   `{"msg":"user not found"}`,
+		},
+		{
+			name:       "wrapped error",
+			err:        fmt.Errorf("loading profile: %w", &notFoundError{id: "42"}),
+			wantStatus: http.StatusNotFound,
+			wantBody:   `{"msg":"user not found"}`,
+		},
+		{
+			name:       "status only uses status text",
+			err:        conflictError{},
+			wantStatus: http.StatusConflict,
+			wantBody:   `{"msg":"Conflict"}`,
+		},
+		{
+			name:       "plain error defaults to 500",
+			err:        errors.New("db password rejected"),
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   `{"msg":"internal error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			responders.ErrorResponse(tt.err).Respond(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+			if got := w.Body.String(); got != tt.wantBody {
+				t.Errorf("expected body %q, got %q", tt.wantBody, got)
+			}
+			if got := w.Header().Get("Content-Type"); got != "application/problem+json" {
+				t.Errorf("expected problem+json, got %q", got)
+			}
+		})
+	}
+}
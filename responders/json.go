@@ -3,14 +3,23 @@
 package responders
 
 import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"log"
 	"net/http"
+	"strconv"
+	"strings"
 )
 
 type jsonResponder struct {
 	body   any
 	status int
+	indent string
+	etag   bool
 }
 
 // JSONResponse creates a responder that serializes the given body to JSON.
@@ -21,20 +30,72 @@ func JSONResponse(body any, status int) *jsonResponder {
 	return &jsonResponder{body: body, status: status}
 }
 
+// Indent switches the responder to pretty-printed output, indenting nested
+// values with the given string. Intended for debugging; the default is compact.
+// Returns the responder for chaining.
+func (r *jsonResponder) Indent(indent string) *jsonResponder {
+	r.indent = indent
+	return r
+}
+
+// ETag makes the responder send a weak ETag computed from the marshaled body.
+// When the request's If-None-Match header matches it, a GET or HEAD request for a 200
+// response receives 304 Not Modified with no body instead.
+// Returns the responder for chaining.
+func (r *jsonResponder) ETag() *jsonResponder {
+	r.etag = true
+	return r
+}
+
 // Respond writes the JSON response to the ResponseWriter.
-// Sets Content-Type to "application/json" and marshals the body.
+// Sets Content-Type to "application/json" and Content-Length, and marshals the body.
 // Panics if marshaling fails, which will be caught by the router's panic recovery.
 func (r *jsonResponder) Respond(w http.ResponseWriter, req *http.Request) {
-	data, err := json.Marshal(r.body)
+	data, err := MarshalJSON(r.body)
+	if err == nil && r.indent != "" {
+		var buf bytes.Buffer
+		if err = json.Indent(&buf, data, "", r.indent); err == nil {
+			data = buf.Bytes()
+		}
+	}
 	if err != nil {
 		panic(fmt.Sprintf("failed to marshal JSON response: %v", err))
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	if r.status > 0 {
-		w.WriteHeader(r.status)
+	if r.etag {
+		tag := weakETag(data)
+		w.Header().Set("ETag", tag)
+		if notModified(req, r.status, tag) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
 	}
-	w.Write(data)
+
+	writeBuffered(w, "application/json", r.status, data)
+}
+
+// weakETag derives a weak entity tag from the exact bytes of a response body.
+func weakETag(data []byte) string {
+	sum := sha256.Sum256(data)
+	return `W/"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// notModified reports whether a conditional GET or HEAD for a 200 response already holds tag,
+// using the weak comparison RFC 9110 prescribes for If-None-Match.
+func notModified(req *http.Request, status int, tag string) bool {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return false
+	}
+	if status != 0 && status != http.StatusOK {
+		return false
+	}
+	for _, candidate := range strings.Split(req.Header.Get("If-None-Match"), ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(tag, "W/") {
+			return true
+		}
+	}
+	return false
 }
 
 type jsonErrorResponder struct {
@@ -58,12 +119,118 @@ type jsonError struct {
 // Sets Content-Type to "application/problem+json" and marshals the error.
 // Panics if marshaling fails, which will be caught by the router's panic recovery.
 func (e *jsonErrorResponder) Respond(w http.ResponseWriter, req *http.Request) {
-	data, err := json.Marshal(jsonError{Msg: e.msg})
+	data, err := MarshalJSON(jsonError{Msg: e.msg})
 	if err != nil {
 		panic(fmt.Sprintf("failed to marshal JSON error response: %v", err))
 	}
 
-	w.Header().Set("Content-Type", "application/problem+json")
-	w.WriteHeader(e.status)
+	writeBuffered(w, "application/problem+json", e.status, data)
+}
+
+// writeBuffered writes a fully marshaled body. Since the whole body is known up front it sets
+// Content-Length, sparing the response chunked encoding. A status of 0 leaves the default 200.
+func writeBuffered(w http.ResponseWriter, contentType string, status int, data []byte) {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	if status > 0 {
+		w.WriteHeader(status)
+	}
 	w.Write(data)
 }
+
+type jsonStreamResponder struct {
+	body   any
+	status int
+}
+
+// JSONStreamResponse creates a responder that encodes the body to the client with a json.Encoder.
+// The encoder still builds the whole encoded value in memory before writing it, so this does not
+// lower peak memory use; for large collections use JSONArrayStream, which writes element by element.
+// Headers and status are sent before encoding starts and no Content-Length is set, so an encoding
+// error cannot change the status and is only logged.
+// The encoder terminates the document with a newline.
+// If status is 0, defaults to 200 OK.
+func JSONStreamResponse(body any, status int) *jsonStreamResponder {
+	return &jsonStreamResponder{body: body, status: status}
+}
+
+// Respond writes the headers and status, then streams the JSON-encoded body.
+func (r *jsonStreamResponder) Respond(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if r.status > 0 {
+		w.WriteHeader(r.status)
+	}
+
+	// A custom codec cannot stream, so its output is written in one piece
+	var err error
+	if c := codec.Load(); c.std {
+		err = json.NewEncoder(w).Encode(r.body)
+	} else {
+		var data []byte
+		if data, err = c.marshal(r.body); err == nil {
+			_, err = w.Write(append(data, '\n'))
+		}
+	}
+	if err != nil {
+		log.Printf("failed to stream JSON response %s %s: %v", req.Method, req.URL.Path, err)
+	}
+}
+
+// jsonArrayFlushEvery is how many elements JSONArrayStream writes between flushes.
+const jsonArrayFlushEvery = 100
+
+type jsonArrayStreamResponder struct {
+	status int
+	next   func() (any, bool, error)
+}
+
+// JSONArrayStream creates a responder that writes a JSON array one element at a time, so
+// exports of any size use constant memory. next is called until it reports false; each element
+// it returns is marshaled with the installed codec and written, and the response is flushed
+// every 100 elements. If next or marshaling fails, the error is logged and the stream stops
+// without the closing bracket, since the status has already been sent; clients see truncated JSON.
+// If status is 0, defaults to 200 OK.
+func JSONArrayStream(status int, next func() (any, bool, error)) *jsonArrayStreamResponder {
+	return &jsonArrayStreamResponder{status: status, next: next}
+}
+
+// Respond writes the headers and status, then streams the array elements.
+func (r *jsonArrayStreamResponder) Respond(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if r.status > 0 {
+		w.WriteHeader(r.status)
+	}
+
+	bw := bufio.NewWriter(w)
+	rc := http.NewResponseController(w)
+	flush := func() {
+		bw.Flush()
+		rc.Flush()
+	}
+	defer flush()
+
+	bw.WriteByte('[')
+	for i := 0; ; i++ {
+		v, ok, err := r.next()
+		if err == nil && !ok {
+			break
+		}
+		var data []byte
+		if err == nil {
+			data, err = MarshalJSON(v)
+		}
+		if err != nil {
+			log.Printf("failed to stream JSON array %s %s at element %d: %v", req.Method, req.URL.Path, i, err)
+			return
+		}
+
+		if i > 0 {
+			bw.WriteByte(',')
+		}
+		bw.Write(data)
+		if (i+1)%jsonArrayFlushEvery == 0 {
+			flush()
+		}
+	}
+	bw.WriteByte(']')
+}
@@ -1,8 +1,16 @@
 package responders_test
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/elmq0022/kami/responders"
@@ -68,6 +76,78 @@ func TestJSONResponder(t *testing.T) {
 	}
 }
 
+func TestJSONResponder_Indent(t *testing.T) {
+	body := map[string]any{"message": "hello", "tags": []string{"a"}}
+
+	t.Run("indented", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		responders.JSONResponse(body, http.StatusOK).Indent("  ").Respond(w, r)
+
+		want := "{\n  \"message\": \"hello\",\n  \"tags\": [\n    \"a\"\n  ]\n}"
+		if got := w.Body.String(); got != want {
+			t.Errorf("expected body %q, got %q", want, got)
+		}
+	})
+
+	t.Run("compact by default", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		responders.JSONResponse(body, http.StatusOK).Respond(w, r)
+
+		if got := w.Body.String(); strings.Contains(got, "\n") {
+			t.Errorf("expected compact body, got %q", got)
+		}
+	})
+}
+
+func TestJSONResponder_ETag(t *testing.T) {
+	body := map[string]string{"message": "hello"}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	responders.JSONResponse(body, http.StatusOK).ETag().Respond(w, r)
+
+	etag := w.Header().Get("ETag")
+	if !strings.HasPrefix(etag, `W/"`) {
+		t.Fatalf("expected weak ETag, got %q", etag)
+	}
+
+	t.Run("matching If-None-Match", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("If-None-Match", `"other", `+etag)
+		responders.JSONResponse(body, http.StatusOK).ETag().Respond(w, r)
+
+		if w.Code != http.StatusNotModified {
+			t.Errorf("expected status 304, got %d", w.Code)
+		}
+		if w.Body.Len() != 0 {
+			t.Errorf("expected empty body, got %q", w.Body.String())
+		}
+		if got := w.Header().Get("ETag"); got != etag {
+			t.Errorf("expected ETag %q, got %q", etag, got)
+		}
+	})
+
+	t.Run("non-matching If-None-Match", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("If-None-Match", `W/"stale"`)
+		responders.JSONResponse(body, http.StatusOK).ETag().Respond(w, r)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("expected status 200, got %d", w.Code)
+		}
+		if got := w.Body.String(); got != `{"message":"hello"}` {
+			t.Errorf("expected body, got %q", got)
+		}
+		if got := w.Header().Get("ETag"); got != etag {
+			t.Errorf("expected ETag %q, got %q", etag, got)
+		}
+	})
+}
+
 func TestJSONResponder_UnmarshalableData(t *testing.T) {
 	defer func() {
 		if r := recover(); r == nil {
@@ -83,6 +163,17 @@ func TestJSONResponder_UnmarshalableData(t *testing.T) {
 	responder.Respond(w, r)
 }
 
+func TestJSONResponder_ContentLength(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	responders.JSONResponse(map[string]string{"message": "hello"}, http.StatusOK).Respond(w, r)
+
+	want := strconv.Itoa(w.Body.Len())
+	if got := w.Header().Get("Content-Length"); got != want {
+		t.Errorf("expected Content-Length %s, got %q", want, got)
+	}
+}
+
 func TestJSONErrorResponder(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -134,3 +225,139 @@ func TestJSONErrorResponder(t *testing.T) {
 		})
 	}
 }
+
+func TestJSONStreamResponder(t *testing.T) {
+	bodies := []any{
+		map[string]string{"message": "hello"},
+		[]int{1, 2, 3},
+		nil,
+	}
+
+	for _, body := range bodies {
+		buffered := httptest.NewRecorder()
+		streamed := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+		responders.JSONResponse(body, http.StatusCreated).Respond(buffered, r)
+		responders.JSONStreamResponse(body, http.StatusCreated).Respond(streamed, r)
+
+		if streamed.Code != buffered.Code {
+			t.Errorf("expected status %d, got %d", buffered.Code, streamed.Code)
+		}
+
+		if got, want := streamed.Header().Get("Content-Type"), buffered.Header().Get("Content-Type"); got != want {
+			t.Errorf("expected Content-Type %q, got %q", want, got)
+		}
+
+		// json.Encoder terminates each document with a newline
+		if got, want := strings.TrimSuffix(streamed.Body.String(), "\n"), buffered.Body.String(); got != want {
+			t.Errorf("expected body %q, got %q", want, got)
+		}
+	}
+}
+
+func TestJSONStreamResponder_EncodeErrorKeepsStatus(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	responders.JSONStreamResponse(make(chan int), http.StatusOK).Respond(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+}
+
+func benchmarkRows() []map[string]any {
+	rows := make([]map[string]any, 10000)
+	for i := range rows {
+		rows[i] = map[string]any{"id": i, "name": "row", "active": true}
+	}
+	return rows
+}
+
+func BenchmarkJSONResponse(b *testing.B) {
+	rows := benchmarkRows()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	b.ReportAllocs()
+	for b.Loop() {
+		responders.JSONResponse(rows, http.StatusOK).Respond(httptest.NewRecorder(), r)
+	}
+}
+
+func BenchmarkJSONStreamResponse(b *testing.B) {
+	rows := benchmarkRows()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	b.ReportAllocs()
+	for b.Loop() {
+		responders.JSONStreamResponse(rows, http.StatusOK).Respond(httptest.NewRecorder(), r)
+	}
+}
+
+func TestJSONArrayStream(t *testing.T) {
+	rows := []map[string]int{{"id": 1}, {"id": 2}, {"id": 3}}
+	i := 0
+	next := func() (any, bool, error) {
+		if i == len(rows) {
+			return nil, false, nil
+		}
+		i++
+		return rows[i-1], true, nil
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/export", nil)
+	responders.JSONArrayStream(http.StatusOK, next).Respond(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d", w.Code)
+	}
+	if got := w.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("expected Content-Type application/json, got %q", got)
+	}
+
+	var got []map[string]int
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("expected valid JSON, got %q: %v", w.Body.String(), err)
+	}
+	if !reflect.DeepEqual(got, rows) {
+		t.Errorf("expected %v, got %v", rows, got)
+	}
+}
+
+func TestJSONArrayStream_Empty(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/export", nil)
+	responders.JSONArrayStream(0, func() (any, bool, error) { return nil, false, nil }).Respond(w, r)
+
+	if got := w.Body.String(); got != "[]" {
+		t.Errorf("expected empty array, got %q", got)
+	}
+}
+
+func TestJSONArrayStream_ErrorStopsStream(t *testing.T) {
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	calls := 0
+	next := func() (any, bool, error) {
+		calls++
+		if calls == 2 {
+			return nil, false, errors.New("cursor closed")
+		}
+		return calls, true, nil
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/export", nil)
+	responders.JSONArrayStream(http.StatusOK, next).Respond(w, r)
+
+	if got := w.Body.String(); got != "[1" {
+		t.Errorf("expected truncated output, got %q", got)
+	}
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status to stay 200, got %d", w.Code)
+	}
+	if !strings.Contains(logs.String(), "cursor closed") {
+		t.Errorf("expected the error to be logged, got %q", logs.String())
+	}
+}
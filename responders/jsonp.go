@@ -0,0 +1,51 @@
+package responders
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+)
+
+// jsonpCallback matches a JavaScript identifier or dotted member path such as "cb" or
+// "jQuery.callbacks._1", which is all a callback name needs and leaves no room for injection.
+var jsonpCallback = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*(\.[A-Za-z_$][A-Za-z0-9_$]*)*$`)
+
+type jsonpResponder struct {
+	body     any
+	callback string
+	status   int
+}
+
+// JSONPResponse creates a responder that serializes body to JSON wrapped in a call to
+// callback, for legacy embeds that load data through a script tag. The callback usually
+// comes from the query string, so it is validated against a safe identifier pattern and
+// an invalid name yields a 400 JSON error instead.
+// If status is 0, defaults to 200 OK.
+// Panics during Respond if the body cannot be marshaled to JSON.
+func JSONPResponse(body any, callback string, status int) *jsonpResponder {
+	return &jsonpResponder{body: body, callback: callback, status: status}
+}
+
+// Respond writes `callback(<json>);` to the ResponseWriter with Content-Type
+// "application/javascript" and Content-Length, or a 400 if the callback name is invalid.
+// Panics if marshaling fails, which will be caught by the router's panic recovery.
+func (j *jsonpResponder) Respond(w http.ResponseWriter, req *http.Request) {
+	if len(j.callback) > 128 || !jsonpCallback.MatchString(j.callback) {
+		JSONErrorResponse("invalid callback name", http.StatusBadRequest).Respond(w, req)
+		return
+	}
+
+	data, err := MarshalJSON(j.body)
+	if err != nil {
+		panic(fmt.Sprintf("failed to marshal JSON response: %v", err))
+	}
+
+	out := make([]byte, 0, len(j.callback)+len(data)+3)
+	out = append(out, j.callback...)
+	out = append(out, '(')
+	out = append(out, data...)
+	out = append(out, ");"...)
+
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	writeBuffered(w, "application/javascript", j.status, out)
+}
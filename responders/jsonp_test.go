@@ -0,0 +1,57 @@
+package responders_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/elmq0022/kami/responders"
+)
+
+func TestJSONPResponse(t *testing.T) {
+	for _, callback := range []string{"cb", "jQuery.callbacks._1", "$handle"} {
+		t.Run(callback, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/?callback="+callback, nil)
+			responders.JSONPResponse(map[string]int{"count": 3}, callback, http.StatusOK).Respond(w, r)
+
+			if w.Code != http.StatusOK {
+				t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+			}
+			if ct := w.Header().Get("Content-Type"); ct != "application/javascript" {
+				t.Errorf("expected Content-Type application/javascript, got %q", ct)
+			}
+			if want := callback + `({"count":3});`; w.Body.String() != want {
+				t.Errorf("expected body %q, got %q", want, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestJSONPResponse_InvalidCallback(t *testing.T) {
+	invalid := []string{
+		"",
+		"alert(1)//",
+		"cb;alert(1)",
+		"1cb",
+		"cb..x",
+		"<script>",
+		strings.Repeat("a", 129),
+	}
+
+	for _, callback := range invalid {
+		t.Run(callback, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			responders.JSONPResponse(map[string]int{"count": 3}, callback, http.StatusOK).Respond(w, r)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+			}
+			if strings.Contains(w.Body.String(), callback) && callback != "" {
+				t.Errorf("expected callback not to be echoed, got %q", w.Body.String())
+			}
+		})
+	}
+}
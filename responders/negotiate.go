@@ -0,0 +1,122 @@
+package responders
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// negotiableTypes lists the media types Negotiate can produce, in server preference order.
+var negotiableTypes = []string{"application/json", "application/xml"}
+
+type negotiateResponder struct {
+	data        any
+	status      int
+	contentType string
+}
+
+// Negotiate creates a responder that serializes data as JSON or XML based on the request's
+// Accept header. Quality values are respected, JSON wins ties, and a missing Accept header
+// yields JSON. When the client accepts neither format the responder writes 406 Not Acceptable.
+// If status is 0, defaults to 200 OK.
+func Negotiate(req *http.Request, data any, status int) *negotiateResponder {
+	return &negotiateResponder{
+		data:        data,
+		status:      status,
+		contentType: negotiateContentType(req.Header.Get("Accept"), negotiableTypes),
+	}
+}
+
+// Respond writes data in the negotiated format with a Vary: Accept header, so caches keep the
+// representations apart.
+// Panics if marshaling fails, which will be caught by the router's panic recovery.
+func (n *negotiateResponder) Respond(w http.ResponseWriter, req *http.Request) {
+	w.Header().Add("Vary", "Accept")
+	switch n.contentType {
+	case "application/json":
+		JSONResponse(n.data, n.status).Respond(w, req)
+	case "application/xml":
+		data, err := xml.Marshal(n.data)
+		if err != nil {
+			panic(fmt.Sprintf("failed to marshal XML response: %v", err))
+		}
+
+		writeBuffered(w, "application/xml", n.status, data)
+	default:
+		JSONErrorResponse(http.StatusText(http.StatusNotAcceptable), http.StatusNotAcceptable).Respond(w, req)
+	}
+}
+
+type acceptRange struct {
+	value string
+	q     float64
+}
+
+// parseAccept splits an Accept-style header into its ranges ordered by descending quality.
+// Ranges with q=0 are kept so they can explicitly exclude a value.
+func parseAccept(header string) []acceptRange {
+	var ranges []acceptRange
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		value := strings.ToLower(strings.TrimSpace(fields[0]))
+		if value == "" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range fields[1:] {
+			k, v, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.EqualFold(k, "q") {
+				if f, err := strconv.ParseFloat(v, 64); err == nil {
+					q = f
+				}
+			}
+		}
+		ranges = append(ranges, acceptRange{value: value, q: q})
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].q > ranges[j].q })
+	return ranges
+}
+
+// negotiateContentType returns the offered media type the client prefers most,
+// or "" when none is acceptable. An empty header accepts the first offer.
+func negotiateContentType(header string, offers []string) string {
+	if strings.TrimSpace(header) == "" {
+		return offers[0]
+	}
+
+	best, bestQ := "", 0.0
+	ranges := parseAccept(header)
+	for _, offer := range offers {
+		// The most specific matching range decides the offer's quality
+		q, specificity := 0.0, -1
+		for _, r := range ranges {
+			s := mediaRangeSpecificity(r.value, offer)
+			if s > specificity {
+				q, specificity = r.q, s
+			}
+		}
+		if specificity >= 0 && q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+	return best
+}
+
+// mediaRangeSpecificity reports how precisely a media range matches a media type:
+// 2 for an exact match, 1 for type/*, 0 for */*, and -1 for no match.
+func mediaRangeSpecificity(mediaRange, mediaType string) int {
+	switch {
+	case mediaRange == mediaType:
+		return 2
+	case mediaRange == "*/*":
+		return 0
+	case strings.HasSuffix(mediaRange, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(mediaRange, "*")):
+		return 1
+	}
+	return -1
+}
@@ -0,0 +1,100 @@
+package responders_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/elmq0022/kami/responders"
+)
+
+type negotiateBook struct {
+	Title string `json:"title" xml:"title"`
+}
+
+func TestNegotiateResponder(t *testing.T) {
+	tests := []struct {
+		name           string
+		accept         string
+		expectedStatus int
+		expectedBody   string
+		expectedCT     string
+	}{
+		{
+			name:           "xml requested",
+			accept:         "application/xml",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `<negotiateBook><title>Dune</title></negotiateBook>`,
+			expectedCT:     "application/xml",
+		},
+		{
+			name:           "no accept header defaults to json",
+			accept:         "",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"title":"Dune"}`,
+			expectedCT:     "application/json",
+		},
+		{
+			name:           "wildcard defaults to json",
+			accept:         "*/*",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"title":"Dune"}`,
+			expectedCT:     "application/json",
+		},
+		{
+			name:           "q-values prefer xml",
+			accept:         "application/json;q=0.5, application/xml;q=0.9",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `<negotiateBook><title>Dune</title></negotiateBook>`,
+			expectedCT:     "application/xml",
+		},
+		{
+			name:           "json excluded by q=0",
+			accept:         "application/json;q=0, */*;q=0.1",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `<negotiateBook><title>Dune</title></negotiateBook>`,
+			expectedCT:     "application/xml",
+		},
+		{
+			name:           "unsupported type",
+			accept:         "text/csv",
+			expectedStatus: http.StatusNotAcceptable,
+			expectedBody:   `{"msg":"Not Acceptable"}`,
+			expectedCT:     "application/problem+json",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.accept != "" {
+				r.Header.Set("Accept", tt.accept)
+			}
+			responders.Negotiate(r, negotiateBook{Title: "Dune"}, http.StatusOK).Respond(w, r)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			if got := w.Header().Get("Content-Type"); got != tt.expectedCT {
+				t.Errorf("expected Content-Type %q, got %q", tt.expectedCT, got)
+			}
+
+			if got := w.Body.String(); got != tt.expectedBody {
+				t.Errorf("expected body %q, got %q", tt.expectedBody, got)
+			}
+		})
+	}
+}
+
+func TestNegotiateResponder_VaryAccept(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/books/1", nil)
+	r.Header.Set("Accept", "application/xml")
+	responders.Negotiate(r, negotiateBook{Title: "Dune"}, http.StatusOK).Respond(w, r)
+
+	if got := w.Header().Get("Vary"); got != "Accept" {
+		t.Errorf("expected Vary %q, got %q", "Accept", got)
+	}
+}
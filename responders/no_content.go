@@ -0,0 +1,15 @@
+package responders
+
+import "net/http"
+
+type noContentResponder struct{}
+
+// NoContentResponse creates a responder that writes a bare 204 No Content status.
+func NoContentResponse() *noContentResponder {
+	return &noContentResponder{}
+}
+
+// Respond writes the 204 status with no body.
+func (n *noContentResponder) Respond(w http.ResponseWriter, req *http.Request) {
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,71 @@
+package responders
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+)
+
+// PageBlock is the block a layout renders the page into, as in {{block "content" .}}{{end}}.
+const PageBlock = "content"
+
+type pageResponder struct {
+	ts     *template.Template
+	layout string
+	page   string
+	data   any
+	status int
+}
+
+// PageResponse creates a responder that renders the page template inside a layout. Both are
+// looked up by name in ts; the page's body replaces the layout's PageBlock block on a clone of
+// ts, so many pages can share one layout without their definitions colliding:
+//
+//	{{define "base"}}<main>{{block "content" .}}{{end}}</main>{{end}}
+//	{{define "users"}}<ul>{{range .}}<li>{{.}}</li>{{end}}</ul>{{end}}
+//
+//	responders.PageResponse(ts, "base", "users", names, http.StatusOK)
+//
+// The output is buffered, so a missing template or an execution error yields a clean
+// plain-text 500 rather than a half-written page. ts itself must not be executed directly,
+// since html/template cannot clone a set after execution. If status is 0, defaults to 200 OK.
+func PageResponse(ts *template.Template, layout, page string, data any, status int) *pageResponder {
+	return &pageResponder{ts: ts, layout: layout, page: page, data: data, status: status}
+}
+
+// Respond renders the page into a buffer and writes it as text/html.
+// Rendering failures are logged and answered with 500 Internal Server Error.
+func (p *pageResponder) Respond(w http.ResponseWriter, req *http.Request) {
+	buf, err := p.render()
+	if err != nil {
+		log.Printf("page response %s %s: %v", req.Method, req.URL.Path, err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	writeBuffered(w, "text/html; charset=utf-8", p.status, buf.Bytes())
+}
+
+func (p *pageResponder) render() (*bytes.Buffer, error) {
+	page := p.ts.Lookup(p.page)
+	if page == nil {
+		return nil, fmt.Errorf("page template %q not found", p.page)
+	}
+
+	ts, err := p.ts.Clone()
+	if err != nil {
+		return nil, err
+	}
+	// Escaping rewrites the tree in place, so each render needs its own copy
+	if _, err := ts.AddParseTree(PageBlock, page.Tree.Copy()); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := ts.ExecuteTemplate(&buf, p.layout, p.data); err != nil {
+		return nil, err
+	}
+	return &buf, nil
+}
@@ -0,0 +1,106 @@
+package responders_test
+
+import (
+	"bytes"
+	"html/template"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/elmq0022/kami/responders"
+)
+
+var pageTemplates = template.Must(template.New("pages").Parse(`
+{{define "base"}}<html><title>{{block "title" .}}Site{{end}}</title><main>{{block "content" .}}{{end}}</main></html>{{end}}
+{{define "users"}}<ul>{{range .}}<li>{{.}}</li>{{end}}</ul>{{end}}
+{{define "about"}}<p>About us</p>{{end}}
+{{define "broken-layout"}}<html>{{template "sidebar" .}}</html>{{end}}
+`))
+
+func TestPageResponse(t *testing.T) {
+	tests := []struct {
+		page string
+		data any
+		want string
+	}{
+		{"users", []string{"Ada", "<Bob>"}, "<html><title>Site</title><main><ul><li>Ada</li><li>&lt;Bob&gt;</li></ul></main></html>"},
+		{"about", nil, "<html><title>Site</title><main><p>About us</p></main></html>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.page, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			responders.PageResponse(pageTemplates, "base", tt.page, tt.data, http.StatusOK).Respond(w, r)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status 200, got %d", w.Code)
+			}
+			if got := w.Header().Get("Content-Type"); got != "text/html; charset=utf-8" {
+				t.Errorf("expected text/html, got %q", got)
+			}
+			if got := w.Body.String(); got != tt.want {
+				t.Errorf("expected body %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestPageResponse_RenderErrors(t *testing.T) {
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	tests := []struct {
+		name   string
+		layout string
+		page   string
+	}{
+		{"missing block", "broken-layout", "about"},
+		{"missing page", "base", "contact"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			responders.PageResponse(pageTemplates, tt.layout, tt.page, nil, http.StatusOK).Respond(w, r)
+
+			if w.Code != http.StatusInternalServerError {
+				t.Fatalf("expected status 500, got %d", w.Code)
+			}
+			if strings.Contains(w.Body.String(), "<html>") {
+				t.Errorf("expected no partial page, got %q", w.Body.String())
+			}
+		})
+	}
+	if !strings.Contains(logs.String(), "page response GET /") {
+		t.Errorf("expected render errors to be logged, got %q", logs.String())
+	}
+}
+
+func TestPageResponse_ParallelRenders(t *testing.T) {
+	ts := template.Must(template.New("pages").Parse(
+		`{{define "base"}}<main>{{block "content" .}}{{end}}</main>{{end}}{{define "users"}}<ul>{{range .}}<li>{{.}}</li>{{end}}</ul>{{end}}`))
+	want := "<main><ul><li>&lt;Ada&gt;</li></ul></main>"
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			responders.PageResponse(ts, "base", "users", []string{"<Ada>"}, http.StatusOK).Respond(w, r)
+
+			if got := w.Body.String(); got != want {
+				t.Errorf("expected body %q, got %q", want, got)
+			}
+		}()
+	}
+	wg.Wait()
+}
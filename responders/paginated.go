@@ -0,0 +1,63 @@
+package responders
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// PageInfo describes where a response sits within a paginated collection.
+// Page is 1-based, PerPage is the page size, and Total is the number of items in the collection.
+type PageInfo struct {
+	Page    int
+	PerPage int
+	Total   int
+}
+
+// LastPage returns the number of the final page, which is at least 1.
+func (p PageInfo) LastPage() int {
+	if p.PerPage <= 0 || p.Total <= 0 {
+		return 1
+	}
+	return (p.Total + p.PerPage - 1) / p.PerPage
+}
+
+type paginatedResponder struct {
+	body   any
+	page   PageInfo
+	status int
+}
+
+// Paginated creates a responder that serves body as JSON and advertises neighbouring
+// pages with an RFC 8288 Link header. The next, prev and last links are built from the
+// request URL by replacing its "page" query parameter; next and prev are omitted on
+// the last and first pages respectively.
+func Paginated(body any, page PageInfo, status int) *paginatedResponder {
+	return &paginatedResponder{body: body, page: page, status: status}
+}
+
+// Respond sets the Link header and writes the JSON body.
+func (p *paginatedResponder) Respond(w http.ResponseWriter, req *http.Request) {
+	last := p.page.LastPage()
+
+	var links []string
+	if p.page.Page < last {
+		links = append(links, pageLink(req, p.page.Page+1, "next"))
+	}
+	if p.page.Page > 1 {
+		links = append(links, pageLink(req, p.page.Page-1, "prev"))
+	}
+	links = append(links, pageLink(req, last, "last"))
+
+	w.Header().Set("Link", strings.Join(links, ", "))
+	JSONResponse(p.body, p.status).Respond(w, req)
+}
+
+func pageLink(req *http.Request, page int, rel string) string {
+	u := *req.URL
+	q := u.Query()
+	q.Set("page", strconv.Itoa(page))
+	u.RawQuery = q.Encode()
+	return fmt.Sprintf(`<%s>; rel="%s"`, u.RequestURI(), rel)
+}
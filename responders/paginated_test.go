@@ -0,0 +1,62 @@
+package responders_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/elmq0022/kami/responders"
+)
+
+func TestPaginatedResponder(t *testing.T) {
+	tests := []struct {
+		name     string
+		page     responders.PageInfo
+		wantLink string
+	}{
+		{
+			name:     "middle page",
+			page:     responders.PageInfo{Page: 2, PerPage: 10, Total: 45},
+			wantLink: `</items?page=3&sort=name>; rel="next", </items?page=1&sort=name>; rel="prev", </items?page=5&sort=name>; rel="last"`,
+		},
+		{
+			name:     "first page",
+			page:     responders.PageInfo{Page: 1, PerPage: 10, Total: 45},
+			wantLink: `</items?page=2&sort=name>; rel="next", </items?page=5&sort=name>; rel="last"`,
+		},
+		{
+			name:     "last page",
+			page:     responders.PageInfo{Page: 5, PerPage: 10, Total: 45},
+			wantLink: `</items?page=4&sort=name>; rel="prev", </items?page=5&sort=name>; rel="last"`,
+		},
+		{
+			name:     "empty collection",
+			page:     responders.PageInfo{Page: 1, PerPage: 10, Total: 0},
+			wantLink: `</items?page=1&sort=name>; rel="last"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/items?sort=name&page=2", nil)
+			responders.Paginated([]string{"a", "b"}, tt.page, http.StatusOK).Respond(w, r)
+
+			if w.Code != http.StatusOK {
+				t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+			}
+
+			if got := w.Header().Get("Link"); got != tt.wantLink {
+				t.Errorf("expected Link %q, got %q", tt.wantLink, got)
+			}
+
+			if got := w.Header().Get("Content-Type"); got != "application/json" {
+				t.Errorf("expected Content-Type %q, got %q", "application/json", got)
+			}
+
+			if got := w.Body.String(); got != `["a","b"]` {
+				t.Errorf("expected body %q, got %q", `["a","b"]`, got)
+			}
+		})
+	}
+}
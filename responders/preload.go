@@ -0,0 +1,61 @@
+package responders
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/elmq0022/kami/types"
+)
+
+// PreloadLink describes a resource the client should start fetching early.
+type PreloadLink struct {
+	// URL is the resource to preload, e.g. "/app.js".
+	URL string
+	// As is the request destination, e.g. "script", "style", "font" or "image".
+	As string
+	// CrossOrigin adds the crossorigin attribute, required for fonts and other CORS fetches.
+	CrossOrigin bool
+}
+
+func (l PreloadLink) String() string {
+	var b strings.Builder
+	b.WriteString("<" + l.URL + ">; rel=preload")
+	if l.As != "" {
+		b.WriteString("; as=" + l.As)
+	}
+	if l.CrossOrigin {
+		b.WriteString("; crossorigin")
+	}
+	return b.String()
+}
+
+type preloadResponder struct {
+	inner      types.Responder
+	links      []PreloadLink
+	earlyHints bool
+}
+
+// WithPreload wraps inner so its response carries a Link: <url>; rel=preload header for each link,
+// letting the browser fetch critical resources before it parses the body.
+func WithPreload(inner types.Responder, links ...PreloadLink) *preloadResponder {
+	return &preloadResponder{inner: inner, links: links}
+}
+
+// EarlyHints also sends the links in a 103 Early Hints informational response before the
+// final response, so clients can start preloading while the inner responder is still working.
+// Returns the responder for chaining.
+func (p *preloadResponder) EarlyHints() *preloadResponder {
+	p.earlyHints = true
+	return p
+}
+
+// Respond adds the Link headers, optionally sends 103 Early Hints, and delegates to the inner responder.
+func (p *preloadResponder) Respond(w http.ResponseWriter, req *http.Request) {
+	for _, l := range p.links {
+		w.Header().Add("Link", l.String())
+	}
+	if p.earlyHints && len(p.links) > 0 {
+		w.WriteHeader(http.StatusEarlyHints)
+	}
+	p.inner.Respond(w, req)
+}
@@ -0,0 +1,75 @@
+package responders_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"net/textproto"
+	"slices"
+	"testing"
+
+	"github.com/elmq0022/kami/responders"
+)
+
+var preloadLinks = []responders.PreloadLink{
+	{URL: "/app.js", As: "script"},
+	{URL: "/font.woff2", As: "font", CrossOrigin: true},
+}
+
+var wantLinks = []string{
+	"</app.js>; rel=preload; as=script",
+	"</font.woff2>; rel=preload; as=font; crossorigin",
+}
+
+func TestWithPreload(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	responders.WithPreload(responders.JSONResponse("ok", http.StatusOK), preloadLinks...).Respond(w, r)
+
+	if got := w.Header().Values("Link"); !slices.Equal(got, wantLinks) {
+		t.Errorf("want Link headers %v, got %v", wantLinks, got)
+	}
+	if w.Code != http.StatusOK || w.Body.String() != `"ok"` {
+		t.Errorf("expected inner response, got %d %q", w.Code, w.Body.String())
+	}
+}
+
+func TestWithPreload_EarlyHints(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		responders.WithPreload(responders.JSONResponse("ok", http.StatusOK), preloadLinks...).EarlyHints().Respond(w, req)
+	}))
+	defer srv.Close()
+
+	var hints []int
+	var hintLinks []string
+	trace := &httptrace.ClientTrace{
+		Got1xxResponse: func(code int, header textproto.MIMEHeader) error {
+			hints = append(hints, code)
+			hintLinks = header.Values("Link")
+			return nil
+		},
+	}
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	if !slices.Equal(hints, []int{http.StatusEarlyHints}) {
+		t.Fatalf("expected one 103 Early Hints response, got %v", hints)
+	}
+	if !slices.Equal(hintLinks, wantLinks) {
+		t.Errorf("want early hint links %v, got %v", wantLinks, hintLinks)
+	}
+	if resp.StatusCode != http.StatusOK || string(body) != `"ok"` {
+		t.Errorf("expected final 200 response, got %d %q", resp.StatusCode, body)
+	}
+	if got := resp.Header.Values("Link"); !slices.Equal(got, wantLinks) {
+		t.Errorf("want final Link headers %v, got %v", wantLinks, got)
+	}
+}
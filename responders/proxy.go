@@ -0,0 +1,70 @@
+package responders
+
+import (
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+)
+
+type proxyResponder struct {
+	target *url.URL
+	strip   string
+	path    string
+	hasPath bool
+}
+
+// ProxyResponse creates a responder that forwards the request to target with
+// httputil.NewSingleHostReverseProxy and streams the upstream response back.
+// The request path is appended to the target's path; use StripPrefix or Path to rewrite it.
+// The upstream request carries the incoming request's context, so it is canceled when the client goes away.
+// Upstream failures produce a 502 Bad Gateway.
+func ProxyResponse(target *url.URL) *proxyResponder {
+	return &proxyResponder{target: target}
+}
+
+// StripPrefix removes prefix from the escaped request path before forwarding,
+// so a route mounted at "/api/*rest" can forward "/api/users" as "/users".
+// Returns the responder for chaining.
+func (p *proxyResponder) StripPrefix(prefix string) *proxyResponder {
+	p.strip = prefix
+	return p
+}
+
+// Path forwards the given escaped path instead of the request path, typically the
+// wildcard captured by the route, which preserves the original encoding:
+//
+//	_, rest := router.GetWildcard(req.Context())
+//	return responders.ProxyResponse(upstream).Path(rest)
+//
+// An empty path, as captured for requests to the mount root, forwards "/".
+// Returns the responder for chaining.
+func (p *proxyResponder) Path(escaped string) *proxyResponder {
+	p.path = escaped
+	p.hasPath = true
+	return p
+}
+
+// Respond proxies the request to the upstream target.
+func (p *proxyResponder) Respond(w http.ResponseWriter, req *http.Request) {
+	escaped := req.URL.EscapedPath()
+	if p.hasPath {
+		escaped = p.path
+	} else if p.strip != "" {
+		escaped = strings.TrimPrefix(escaped, p.strip)
+	}
+	if !strings.HasPrefix(escaped, "/") {
+		escaped = "/" + escaped
+	}
+
+	out := req.Clone(req.Context())
+	out.URL.RawPath = escaped
+	if path, err := url.PathUnescape(escaped); err == nil {
+		out.URL.Path = path
+	} else {
+		out.URL.Path = escaped
+		out.URL.RawPath = ""
+	}
+
+	httputil.NewSingleHostReverseProxy(p.target).ServeHTTP(w, out)
+}
@@ -0,0 +1,120 @@
+package responders_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/elmq0022/kami/responders"
+	"github.com/elmq0022/kami/router"
+	"github.com/elmq0022/kami/types"
+)
+
+func newUpstream(t *testing.T) *url.URL {
+	t.Helper()
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("X-Upstream-Path", req.URL.EscapedPath())
+		w.Header().Set("X-Upstream-Forwarded-For", req.Header.Get("X-Forwarded-For"))
+		io.WriteString(w, "hello from upstream")
+	}))
+	t.Cleanup(upstream.Close)
+
+	target, err := url.Parse(upstream.URL + "/base")
+	if err != nil {
+		t.Fatalf("failed to parse upstream URL: %v", err)
+	}
+	return target
+}
+
+func TestProxyResponse(t *testing.T) {
+	target := newUpstream(t)
+
+	tests := []struct {
+		name     string
+		strip    string
+		path     *string
+		reqPath  string
+		wantPath string
+	}{
+		{name: "request path", reqPath: "/users/1", wantPath: "/base/users/1"},
+		{name: "strip prefix", strip: "/api", reqPath: "/api/users/1", wantPath: "/base/users/1"},
+		{name: "explicit encoded path", path: ptr("a%2Fb"), reqPath: "/files/a%2Fb", wantPath: "/base/a%2Fb"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := responders.ProxyResponse(target).StripPrefix(tt.strip)
+			if tt.path != nil {
+				p.Path(*tt.path)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, tt.reqPath, nil)
+			req.RemoteAddr = "203.0.113.7:5555"
+			w := httptest.NewRecorder()
+			p.Respond(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+			}
+			if w.Body.String() != "hello from upstream" {
+				t.Errorf("expected upstream body, got %q", w.Body.String())
+			}
+			if got := w.Header().Get("X-Upstream-Path"); got != tt.wantPath {
+				t.Errorf("expected upstream path %q, got %q", tt.wantPath, got)
+			}
+			if got := w.Header().Get("X-Upstream-Forwarded-For"); got != "203.0.113.7" {
+				t.Errorf("expected X-Forwarded-For 203.0.113.7, got %q", got)
+			}
+		})
+	}
+}
+
+func ptr(s string) *string { return &s }
+
+func TestProxyResponse_MountRoot(t *testing.T) {
+	target := newUpstream(t)
+	r, err := router.New()
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+	r.Prefix("/api/*rest").GET(func(req *http.Request) types.Responder {
+		_, rest := router.GetWildcard(req.Context())
+		return responders.ProxyResponse(target).Path(rest)
+	})
+
+	tests := []struct {
+		reqPath  string
+		wantPath string
+	}{
+		{reqPath: "/api", wantPath: "/base/"},
+		{reqPath: "/api/", wantPath: "/base/"},
+		{reqPath: "/api/users", wantPath: "/base/users"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.reqPath, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.reqPath, nil))
+
+			if got := w.Header().Get("X-Upstream-Path"); got != tt.wantPath {
+				t.Errorf("expected upstream path %q, got %q", tt.wantPath, got)
+			}
+		})
+	}
+}
+
+func TestProxyResponse_UpstreamDown(t *testing.T) {
+	upstream := httptest.NewServer(http.NotFoundHandler())
+	target, _ := url.Parse(upstream.URL)
+	upstream.Close()
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	responders.ProxyResponse(target).Respond(w, req)
+
+	if w.Code != http.StatusBadGateway {
+		t.Fatalf("expected status %d, got %d", http.StatusBadGateway, w.Code)
+	}
+}
@@ -0,0 +1,40 @@
+package responders
+
+import (
+	"io"
+	"log"
+	"net/http"
+)
+
+type readerResponder struct {
+	reader      io.Reader
+	contentType string
+	status      int
+}
+
+// ReaderResponse creates a responder that copies the reader to the response body with the
+// given content type. If status is 0, defaults to 200 OK. If the reader implements io.Closer
+// it is closed after the copy. Use StreamResponse instead when data should be flushed to
+// the client as it is produced.
+func ReaderResponse(r io.Reader, contentType string, status int) *readerResponder {
+	return &readerResponder{reader: r, contentType: contentType, status: status}
+}
+
+// Respond writes the headers and status, then copies the reader to the ResponseWriter.
+// Copy errors after the status has been sent can only be logged.
+func (rr *readerResponder) Respond(w http.ResponseWriter, req *http.Request) {
+	if c, ok := rr.reader.(io.Closer); ok {
+		defer c.Close()
+	}
+
+	if rr.contentType != "" {
+		w.Header().Set("Content-Type", rr.contentType)
+	}
+	if rr.status > 0 {
+		w.WriteHeader(rr.status)
+	}
+
+	if _, err := io.Copy(w, rr.reader); err != nil {
+		log.Printf("reader response %s %s: %v", req.Method, req.URL.Path, err)
+	}
+}
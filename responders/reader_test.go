@@ -0,0 +1,55 @@
+package responders_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/elmq0022/kami/responders"
+)
+
+type closeTracker struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeTracker) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestReaderResponder(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	responders.ReaderResponse(strings.NewReader("a,b,c\n1,2,3\n"), "text/csv", http.StatusOK).Respond(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	if got := w.Header().Get("Content-Type"); got != "text/csv" {
+		t.Errorf("expected Content-Type %q, got %q", "text/csv", got)
+	}
+
+	if got := w.Body.String(); got != "a,b,c\n1,2,3\n" {
+		t.Errorf("expected body %q, got %q", "a,b,c\n1,2,3\n", got)
+	}
+}
+
+func TestReaderResponder_ClosesReader(t *testing.T) {
+	rc := &closeTracker{Reader: strings.NewReader("data")}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	responders.ReaderResponse(rc, "application/octet-stream", 0).Respond(w, r)
+
+	if !rc.closed {
+		t.Error("expected reader to be closed")
+	}
+
+	if got := w.Body.String(); got != "data" {
+		t.Errorf("expected body %q, got %q", "data", got)
+	}
+}
@@ -1,14 +1,48 @@
 package responders
 
 import (
+	"bytes"
+	"io"
 	"io/fs"
+	"mime"
 	"net/http"
+	"path"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/elmq0022/kami/types"
 )
 
+// StaticOptions configures how a static responder serves files.
+// The zero value serves files without any caching headers.
+type StaticOptions struct {
+	// MaxAge sets Cache-Control: public, max-age=<seconds> on served files when positive.
+	MaxAge time.Duration
+
+	// Immutable adds the immutable directive to Cache-Control, telling browsers a
+	// fingerprinted asset never changes. Only applies when MaxAge is set.
+	Immutable bool
+
+	// PreferPrecompressed serves a precompressed sibling of the requested file, such as
+	// app.js.br or app.js.gz next to app.js, when the client accepts that encoding.
+	// The sibling is sent with Content-Encoding and the original file's content type,
+	// and responses carry Vary: Accept-Encoding. Brotli is preferred over gzip.
+	PreferPrecompressed bool
+
+	// NotFound handles requests for files that do not exist, for example to answer with the
+	// same JSON 404 as the rest of an API. When nil, http.FileServer writes its plain-text 404.
+	NotFound types.Handler
+
+	// FallThrough makes Router.ServeStatic set NotFound to the router's not-found handler,
+	// including any scoped with Router.NotFound. It is ignored when NotFound is set.
+	FallThrough bool
+}
+
 type staticDirectoryResponder struct {
 	FS      fs.FS
 	Prefix  string
+	Options StaticOptions
 	handler http.Handler
 }
 
@@ -16,13 +50,21 @@ type staticDirectoryResponder struct {
 // The prefix is the URL path prefix that will be stripped before looking up files in the FS.
 // For example, with prefix "/static" and FS containing "index.html",
 // a request to "/static/index.html" will serve the file.
-// Delegates to http.FileServer for actual file serving.
-func NewStaticDirResponder(f fs.FS, prefix string) *staticDirectoryResponder {
+// Regular files are served with http.ServeContent, so Range and conditional requests work
+// for any fs.FS, including embed.FS. Directories delegate to http.FileServer.
+// An optional StaticOptions configures caching; only the first value is used.
+func NewStaticDirResponder(f fs.FS, prefix string, opts ...StaticOptions) *staticDirectoryResponder {
 	fsHandler := http.StripPrefix(prefix, http.FileServer(http.FS(f)))
 
+	var o StaticOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+
 	return &staticDirectoryResponder{
 		FS:      f,
 		Prefix:  prefix,
+		Options: o,
 		handler: fsHandler,
 	}
 }
@@ -30,10 +72,18 @@ func NewStaticDirResponder(f fs.FS, prefix string) *staticDirectoryResponder {
 // Respond serves static files from the configured filesystem.
 // Automatically redirects directory requests to include a trailing slash.
 // For example, "/static/dir" redirects to "/static/dir/" with a 301 status.
-// Delegates to the underlying http.FileServer for actual file serving and security.
+// Files that do not implement io.Seeker are buffered in memory so they can still be served.
+// Missing files are passed to Options.NotFound when it is set.
 func (r *staticDirectoryResponder) Respond(w http.ResponseWriter, req *http.Request) {
 	trimmed := strings.TrimPrefix(req.URL.Path, r.Prefix)
 
+	if r.Options.NotFound != nil {
+		if _, err := fs.Stat(r.FS, fsName(trimmed)); err != nil {
+			r.Options.NotFound(req).Respond(w, req)
+			return
+		}
+	}
+
 	// If the URL path does not end with "/" and is a directory (or empty), redirect
 	if !strings.HasSuffix(req.URL.Path, "/") {
 		// Empty path is the root of FS
@@ -43,7 +93,7 @@ func (r *staticDirectoryResponder) Respond(w http.ResponseWriter, req *http.Requ
 		}
 
 		// Otherwise, check FS
-		if dir, err := r.FS.Open(trimmed); err == nil {
+		if dir, err := r.FS.Open(fsName(trimmed)); err == nil {
 			if info, err := dir.Stat(); err == nil && info.IsDir() {
 				http.Redirect(w, req, req.URL.Path+"/", http.StatusMovedPermanently)
 				return
@@ -51,5 +101,112 @@ func (r *staticDirectoryResponder) Respond(w http.ResponseWriter, req *http.Requ
 		}
 	}
 
+	if r.serveFile(w, req, fsName(trimmed)) {
+		return
+	}
+
 	r.handler.ServeHTTP(w, req)
 }
+
+// precompressedEncodings maps content codings to file suffixes, in order of preference.
+var precompressedEncodings = []struct {
+	coding string
+	suffix string
+}{
+	{"br", ".br"},
+	{"gzip", ".gz"},
+}
+
+// serveFile serves name if it is a regular file and reports whether it did.
+// With PreferPrecompressed, a precompressed sibling the client accepts is served instead.
+func (r *staticDirectoryResponder) serveFile(w http.ResponseWriter, req *http.Request, name string) bool {
+	if r.Options.PreferPrecompressed {
+		w.Header().Add("Vary", "Accept-Encoding")
+		if contentType := mime.TypeByExtension(path.Ext(name)); contentType != "" {
+			accept := req.Header.Get("Accept-Encoding")
+			for _, enc := range precompressedEncodings {
+				if !acceptsEncoding(accept, enc.coding) {
+					continue
+				}
+				if r.serveRegular(w, req, name+enc.suffix, func(h http.Header) {
+					h.Set("Content-Type", contentType)
+					h.Set("Content-Encoding", enc.coding)
+				}) {
+					return true
+				}
+			}
+		}
+	}
+
+	return r.serveRegular(w, req, name, nil)
+}
+
+// serveRegular serves name if it is a regular file and reports whether it did.
+// setHeaders, when non-nil, adjusts the headers before the content is served.
+func (r *staticDirectoryResponder) serveRegular(w http.ResponseWriter, req *http.Request, name string, setHeaders func(http.Header)) bool {
+	f, err := r.FS.Open(name)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil || !info.Mode().IsRegular() {
+		return false
+	}
+
+	content, ok := f.(io.ReadSeeker)
+	if !ok {
+		data, err := io.ReadAll(f)
+		if err != nil {
+			return false
+		}
+		content = bytes.NewReader(data)
+	}
+
+	if cc := r.Options.cacheControl(); cc != "" {
+		w.Header().Set("Cache-Control", cc)
+	}
+	if setHeaders != nil {
+		setHeaders(w.Header())
+	}
+
+	http.ServeContent(w, req, info.Name(), info.ModTime(), content)
+	return true
+}
+
+func (o StaticOptions) cacheControl() string {
+	if o.MaxAge <= 0 {
+		return ""
+	}
+
+	cc := "public, max-age=" + strconv.Itoa(int(o.MaxAge.Seconds()))
+	if o.Immutable {
+		cc += ", immutable"
+	}
+	return cc
+}
+
+// acceptsEncoding reports whether an Accept-Encoding header allows coding, honoring q=0
+// exclusions and the "*" wildcard.
+func acceptsEncoding(header, coding string) bool {
+	wildcard := false
+	for _, r := range parseAccept(header) {
+		switch r.value {
+		case coding:
+			return r.q > 0
+		case "*":
+			wildcard = r.q > 0
+		}
+	}
+	return wildcard
+}
+
+// fsName converts a URL path relative to the prefix into a cleaned fs.FS name.
+func fsName(p string) string {
+	name := strings.TrimPrefix(path.Clean("/"+p), "/")
+	if name == "" {
+		return "."
+	}
+	return name
+}
@@ -1 +1,248 @@
 package responders_test
+
+import (
+	"embed"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/elmq0022/kami/responders"
+	"github.com/elmq0022/kami/types"
+)
+
+//go:embed testdata/static
+var embeddedStatic embed.FS
+
+func staticFS(t *testing.T) fs.FS {
+	t.Helper()
+	sub, err := fs.Sub(embeddedStatic, "testdata/static")
+	if err != nil {
+		t.Fatalf("failed to open embedded fs: %v", err)
+	}
+	return sub
+}
+
+func TestStaticDirResponder_RangeRequest(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/static/hello.txt", nil)
+	r.Header.Set("Range", "bytes=0-3")
+	responders.NewStaticDirResponder(staticFS(t), "/static").Respond(w, r)
+
+	if w.Code != http.StatusPartialContent {
+		t.Fatalf("expected status %d, got %d", http.StatusPartialContent, w.Code)
+	}
+
+	if got := w.Body.String(); got != "hell" {
+		t.Errorf("expected body %q, got %q", "hell", got)
+	}
+
+	if got := w.Header().Get("Content-Range"); got != "bytes 0-3/12" {
+		t.Errorf("expected Content-Range %q, got %q", "bytes 0-3/12", got)
+	}
+}
+
+func TestStaticDirResponder_FullFile(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/static/hello.txt", nil)
+	responders.NewStaticDirResponder(staticFS(t), "/static").Respond(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	if got := w.Body.String(); got != "hello, range" {
+		t.Errorf("expected body %q, got %q", "hello, range", got)
+	}
+
+	if got := w.Header().Get("Content-Type"); got != "text/plain; charset=utf-8" {
+		t.Errorf("expected Content-Type %q, got %q", "text/plain; charset=utf-8", got)
+	}
+}
+
+// unseekableFS wraps an fs.FS so its files only implement fs.File.
+type unseekableFS struct{ fs.FS }
+
+type unseekableFile struct{ f fs.File }
+
+func (u unseekableFS) Open(name string) (fs.File, error) {
+	f, err := u.FS.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return unseekableFile{f}, nil
+}
+
+func (u unseekableFile) Stat() (fs.FileInfo, error) { return u.f.Stat() }
+func (u unseekableFile) Read(p []byte) (int, error) { return u.f.Read(p) }
+func (u unseekableFile) Close() error               { return u.f.Close() }
+
+func TestStaticDirResponder_UnseekableFile(t *testing.T) {
+	fsys := unseekableFS{fstest.MapFS{"data.txt": {Data: []byte("0123456789")}}}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/data.txt", nil)
+	r.Header.Set("Range", "bytes=2-4")
+	responders.NewStaticDirResponder(fsys, "").Respond(w, r)
+
+	if w.Code != http.StatusPartialContent {
+		t.Fatalf("expected status %d, got %d", http.StatusPartialContent, w.Code)
+	}
+
+	if got, _ := io.ReadAll(w.Body); string(got) != "234" {
+		t.Errorf("expected body %q, got %q", "234", got)
+	}
+}
+
+func TestStaticDirResponder_DirectoryRedirect(t *testing.T) {
+	fsys := fstest.MapFS{"docs/index.html": {Data: []byte("<h1>docs</h1>")}}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/static/docs", nil)
+	responders.NewStaticDirResponder(fsys, "/static").Respond(w, r)
+
+	if w.Code != http.StatusMovedPermanently {
+		t.Fatalf("expected status %d, got %d", http.StatusMovedPermanently, w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodGet, "/static/docs/", nil)
+	responders.NewStaticDirResponder(fsys, "/static").Respond(w, r)
+
+	if got := w.Body.String(); got != "<h1>docs</h1>" {
+		t.Errorf("expected directory index %q, got %q", "<h1>docs</h1>", got)
+	}
+}
+
+func TestStaticDirResponder_CacheControl(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []responders.StaticOptions
+		expected string
+	}{
+		{name: "no caching by default", opts: nil, expected: ""},
+		{name: "max age", opts: []responders.StaticOptions{{MaxAge: time.Hour}}, expected: "public, max-age=3600"},
+		{name: "immutable", opts: []responders.StaticOptions{{MaxAge: 365 * 24 * time.Hour, Immutable: true}}, expected: "public, max-age=31536000, immutable"},
+		{name: "immutable without max age", opts: []responders.StaticOptions{{Immutable: true}}, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/static/hello.txt", nil)
+			responders.NewStaticDirResponder(staticFS(t), "/static", tt.opts...).Respond(w, r)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+			}
+
+			if got := w.Header().Get("Cache-Control"); got != tt.expected {
+				t.Errorf("expected Cache-Control %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestStaticDirResponder_NoCacheOnMiss(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/static/missing.txt", nil)
+	responders.NewStaticDirResponder(staticFS(t), "/static", responders.StaticOptions{MaxAge: time.Hour}).Respond(w, r)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+
+	if got := w.Header().Get("Cache-Control"); got != "" {
+		t.Errorf("expected no Cache-Control on a miss, got %q", got)
+	}
+}
+
+func TestStaticDirResponder_NotFoundHandler(t *testing.T) {
+	opts := responders.StaticOptions{
+		NotFound: func(req *http.Request) types.Responder {
+			return responders.JSONErrorResponse("no such asset", http.StatusNotFound)
+		},
+	}
+	responder := responders.NewStaticDirResponder(staticFS(t), "/static", opts)
+
+	t.Run("existing file", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		responder.Respond(w, httptest.NewRequest(http.MethodGet, "/static/hello.txt", nil))
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		responder.Respond(w, httptest.NewRequest(http.MethodGet, "/static/missing.txt", nil))
+
+		if w.Code != http.StatusNotFound {
+			t.Fatalf("expected status %d, got %d", http.StatusNotFound, w.Code)
+		}
+		if got := w.Body.String(); got != `{"msg":"no such asset"}` {
+			t.Errorf("expected custom 404 body, got %q", got)
+		}
+	})
+}
+
+func TestStaticDirResponder_PreferPrecompressed(t *testing.T) {
+	files := fstest.MapFS{
+		"app.js":    {Data: []byte("console.log('plain')")},
+		"app.js.gz": {Data: []byte("gzip bytes")},
+		"app.js.br": {Data: []byte("brotli bytes")},
+		"style.css": {Data: []byte("body{}")},
+	}
+	responder := responders.NewStaticDirResponder(files, "/static", responders.StaticOptions{PreferPrecompressed: true})
+
+	tests := []struct {
+		name         string
+		path         string
+		accept       string
+		wantBody     string
+		wantEncoding string
+	}{
+		{"gzip accepted", "/static/app.js", "gzip, deflate", "gzip bytes", "gzip"},
+		{"brotli preferred", "/static/app.js", "gzip, br", "brotli bytes", "br"},
+		{"brotli refused", "/static/app.js", "br;q=0, *", "gzip bytes", "gzip"},
+		{"no encoding accepted", "/static/app.js", "", "console.log('plain')", ""},
+		{"identity only", "/static/app.js", "identity", "console.log('plain')", ""},
+		{"no sibling", "/static/style.css", "gzip", "body{}", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.accept != "" {
+				r.Header.Set("Accept-Encoding", tt.accept)
+			}
+			responder.Respond(w, r)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+			}
+			if got := w.Body.String(); got != tt.wantBody {
+				t.Errorf("expected body %q, got %q", tt.wantBody, got)
+			}
+			if got := w.Header().Get("Content-Encoding"); got != tt.wantEncoding {
+				t.Errorf("expected Content-Encoding %q, got %q", tt.wantEncoding, got)
+			}
+			if got := w.Header().Get("Vary"); got != "Accept-Encoding" {
+				t.Errorf("expected Vary: Accept-Encoding, got %q", got)
+			}
+		})
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/static/app.js", nil)
+	r.Header.Set("Accept-Encoding", "gzip")
+	responder.Respond(w, r)
+	if got := w.Header().Get("Content-Type"); got != "text/javascript; charset=utf-8" {
+		t.Errorf("expected the original content type, got %q", got)
+	}
+}
@@ -0,0 +1,82 @@
+package responders
+
+import (
+	"io"
+	"log"
+	"net/http"
+)
+
+type streamResponder struct {
+	reader      io.Reader
+	contentType string
+	status      int
+	maxBytes    int64
+}
+
+// StreamResponse creates a responder that copies the reader to the client,
+// flushing after every write so data reaches the client as it is produced.
+// If status is 0, defaults to 200 OK. If the reader implements io.Closer it is
+// closed once streaming finishes.
+func StreamResponse(r io.Reader, contentType string, status int) *streamResponder {
+	return &streamResponder{reader: r, contentType: contentType, status: status}
+}
+
+// MaxBytes caps the number of bytes written to the client.
+// When the reader produces more than n bytes, streaming stops at the limit and
+// the truncation is logged. A value of 0 or less means no limit.
+func (s *streamResponder) MaxBytes(n int64) *streamResponder {
+	s.maxBytes = n
+	return s
+}
+
+// Respond writes the headers and status, then streams the reader to the ResponseWriter.
+// Errors after the status has been sent can only be logged.
+func (s *streamResponder) Respond(w http.ResponseWriter, req *http.Request) {
+	if c, ok := s.reader.(io.Closer); ok {
+		defer c.Close()
+	}
+
+	if s.contentType != "" {
+		w.Header().Set("Content-Type", s.contentType)
+	}
+	if s.status > 0 {
+		w.WriteHeader(s.status)
+	}
+
+	fw := &flushWriter{w: w, rc: http.NewResponseController(w)}
+
+	if s.maxBytes <= 0 {
+		if _, err := io.Copy(fw, s.reader); err != nil {
+			log.Printf("stream %s %s: %v", req.Method, req.URL.Path, err)
+		}
+		return
+	}
+
+	n, err := io.CopyN(fw, s.reader, s.maxBytes)
+	if err != nil {
+		if err != io.EOF {
+			log.Printf("stream %s %s: %v", req.Method, req.URL.Path, err)
+		}
+		return
+	}
+
+	// The limit was reached; probe for more data to tell a reader that ended
+	// exactly at the limit apart from one that would keep going.
+	var probe [1]byte
+	if m, _ := io.ReadFull(s.reader, probe[:]); m > 0 {
+		log.Printf("stream %s %s: truncated at %d bytes", req.Method, req.URL.Path, n)
+	}
+}
+
+// flushWriter flushes after every write. The ResponseController sees through writers wrapped
+// by middleware; writers that cannot flush are written to unflushed.
+type flushWriter struct {
+	w  io.Writer
+	rc *http.ResponseController
+}
+
+func (fw *flushWriter) Write(p []byte) (int, error) {
+	n, err := fw.w.Write(p)
+	fw.rc.Flush()
+	return n, err
+}
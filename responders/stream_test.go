@@ -0,0 +1,81 @@
+package responders_test
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/elmq0022/kami/responders"
+)
+
+// endlessReader produces an unbounded stream of 'a' bytes.
+type endlessReader struct{}
+
+func (endlessReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 'a'
+	}
+	return len(p), nil
+}
+
+func TestStreamResponder(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	responders.StreamResponse(strings.NewReader("hello stream"), "text/plain", http.StatusOK).Respond(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	if got := w.Header().Get("Content-Type"); got != "text/plain" {
+		t.Errorf("expected Content-Type %q, got %q", "text/plain", got)
+	}
+
+	if got := w.Body.String(); got != "hello stream" {
+		t.Errorf("expected body %q, got %q", "hello stream", got)
+	}
+
+	if !w.Flushed {
+		t.Error("expected the response to be flushed")
+	}
+}
+
+func TestStreamResponder_MaxBytes(t *testing.T) {
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	responders.StreamResponse(endlessReader{}, "text/plain", http.StatusOK).MaxBytes(1024).Respond(w, r)
+
+	if got := w.Body.Len(); got != 1024 {
+		t.Errorf("expected body truncated at %d bytes, got %d", 1024, got)
+	}
+
+	if !strings.Contains(logs.String(), "truncated at 1024 bytes") {
+		t.Errorf("expected truncation to be logged, got %q", logs.String())
+	}
+}
+
+func TestStreamResponder_MaxBytesNotReached(t *testing.T) {
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	responders.StreamResponse(strings.NewReader("exact"), "text/plain", 0).MaxBytes(5).Respond(w, r)
+
+	if got := w.Body.String(); got != "exact" {
+		t.Errorf("expected body %q, got %q", "exact", got)
+	}
+
+	if logs.Len() != 0 {
+		t.Errorf("expected nothing logged, got %q", logs.String())
+	}
+}
@@ -0,0 +1,42 @@
+package responders
+
+import (
+	"reflect"
+
+	"github.com/elmq0022/kami/types"
+)
+
+// Switch maps err to a responder by looking up each error in its wrap chain in cases.
+// The chain is walked from the outermost error inwards, following both Unwrap() error and
+// Unwrap() []error, and the first error of type E present in cases selects the responder.
+// Keys are compared with ==, so E is typically error (matching sentinel values) or a
+// comparable custom error type. Errors in the chain whose dynamic type cannot be compared, such as
+// map-based errors, never match rather than panicking. When nothing matches, fallback is returned.
+func Switch[E interface {
+	error
+	comparable
+}](err error, cases map[E]func(E) types.Responder, fallback types.Responder) types.Responder {
+	if err == nil {
+		return fallback
+	}
+
+	// Indexing the map with an unhashable dynamic type panics, so skip those errors
+	if e, ok := err.(E); ok && reflect.TypeOf(err).Comparable() {
+		if fn, ok := cases[e]; ok {
+			return fn(e)
+		}
+	}
+
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		return Switch(u.Unwrap(), cases, fallback)
+	case interface{ Unwrap() []error }:
+		for _, inner := range u.Unwrap() {
+			if r := Switch(inner, cases, nil); r != nil {
+				return r
+			}
+		}
+	}
+
+	return fallback
+}
@@ -0,0 +1,107 @@
+package responders_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/elmq0022/kami/binding"
+	"github.com/elmq0022/kami/responders"
+	"github.com/elmq0022/kami/types"
+)
+
+type notFoundErr struct{ resource string }
+
+func (e notFoundErr) Error() string { return e.resource + " not found" }
+
+type conflictErr struct{ reason string }
+
+func (e *conflictErr) Error() string { return "conflict: " + e.reason }
+
+var (
+	errUserNotFound = notFoundErr{resource: "user"}
+	errDuplicate    = &conflictErr{reason: "duplicate email"}
+)
+
+func TestSwitch(t *testing.T) {
+	cases := map[error]func(error) types.Responder{
+		errUserNotFound: func(err error) types.Responder {
+			return responders.JSONErrorResponse(err.Error(), http.StatusNotFound)
+		},
+		errDuplicate: func(err error) types.Responder {
+			return responders.JSONErrorResponse(err.Error(), http.StatusConflict)
+		},
+	}
+	fallback := responders.JSONErrorResponse("internal error", http.StatusInternalServerError)
+
+	tests := []struct {
+		name         string
+		err          error
+		expectedCode int
+		expectedBody string
+	}{
+		{name: "first error type", err: errUserNotFound, expectedCode: http.StatusNotFound, expectedBody: `{"msg":"user not found"}`},
+		{name: "second error type", err: errDuplicate, expectedCode: http.StatusConflict, expectedBody: `{"msg":"conflict: duplicate email"}`},
+		{name: "wrapped error", err: fmt.Errorf("saving: %w", errDuplicate), expectedCode: http.StatusConflict, expectedBody: `{"msg":"conflict: duplicate email"}`},
+		{name: "joined error", err: errors.Join(errors.New("other"), errUserNotFound), expectedCode: http.StatusNotFound, expectedBody: `{"msg":"user not found"}`},
+		{name: "unmatched error", err: errors.New("boom"), expectedCode: http.StatusInternalServerError, expectedBody: `{"msg":"internal error"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			responders.Switch(tt.err, cases, fallback).Respond(w, r)
+
+			if w.Code != tt.expectedCode {
+				t.Errorf("expected status %d, got %d", tt.expectedCode, w.Code)
+			}
+
+			if got := w.Body.String(); got != tt.expectedBody {
+				t.Errorf("expected body %q, got %q", tt.expectedBody, got)
+			}
+		})
+	}
+}
+
+func TestSwitch_ConcreteErrorType(t *testing.T) {
+	cases := map[notFoundErr]func(notFoundErr) types.Responder{
+		{resource: "book"}: func(err notFoundErr) types.Responder {
+			return responders.JSONErrorResponse("no such "+err.resource, http.StatusNotFound)
+		},
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	responders.Switch(fmt.Errorf("lookup: %w", notFoundErr{resource: "book"}), cases, nil).Respond(w, r)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestSwitch_UnhashableError(t *testing.T) {
+	cases := map[error]func(error) types.Responder{
+		errUserNotFound: func(err error) types.Responder {
+			return responders.JSONErrorResponse(err.Error(), http.StatusNotFound)
+		},
+	}
+	fallback := responders.JSONErrorResponse("internal error", http.StatusInternalServerError)
+	invalid := binding.ValidationErrors{"email": {"is required"}}
+
+	for _, err := range []error{invalid, fmt.Errorf("binding: %w", invalid), errors.Join(invalid, errUserNotFound)} {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		responders.Switch(err, cases, fallback).Respond(w, r)
+
+		want := http.StatusInternalServerError
+		if errors.Is(err, errUserNotFound) {
+			want = http.StatusNotFound
+		}
+		if w.Code != want {
+			t.Errorf("%v: expected status %d, got %d", err, want, w.Code)
+		}
+	}
+}
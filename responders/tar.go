@@ -0,0 +1,104 @@
+package responders
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"mime"
+	"net/http"
+)
+
+type tarResponder struct {
+	fsys     fs.FS
+	paths    []string
+	filename string
+}
+
+// Tar creates a responder that streams a tar archive of the given paths from fsys as a download.
+// Directories are included recursively. Paths must be valid fs.FS paths, so absolute paths and
+// ".." traversal are rejected with 400 Bad Request; missing paths produce 404 Not Found.
+// The archive is written entry by entry rather than buffered in memory.
+func Tar(fsys fs.FS, paths []string, filename string) *tarResponder {
+	return &tarResponder{fsys: fsys, paths: paths, filename: filename}
+}
+
+// Respond validates the paths, then writes the archive with Content-Type "application/x-tar".
+// Errors after the first byte has been written can only be logged.
+func (t *tarResponder) Respond(w http.ResponseWriter, req *http.Request) {
+	files, status, err := archiveFiles(t.fsys, t.paths)
+	if err != nil {
+		JSONErrorResponse(err.Error(), status).Respond(w, req)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-tar")
+	w.Header().Set("Content-Disposition", attachmentDisposition(t.filename))
+	w.WriteHeader(http.StatusOK)
+
+	tw := tar.NewWriter(w)
+	for _, name := range files {
+		if err := writeTarEntry(tw, t.fsys, name); err != nil {
+			log.Printf("tar %s: %v", name, err)
+			return
+		}
+	}
+	if err := tw.Close(); err != nil {
+		log.Printf("tar: %v", err)
+	}
+}
+
+func writeTarEntry(tw *tar.Writer, fsys fs.FS, name string) error {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	hdr, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	hdr.Name = name
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+// archiveFiles expands paths into the regular files they refer to, walking directories.
+// It returns an HTTP status alongside any error so callers can report it before streaming.
+func archiveFiles(fsys fs.FS, paths []string) ([]string, int, error) {
+	var files []string
+	for _, p := range paths {
+		if !fs.ValidPath(p) {
+			return nil, http.StatusBadRequest, fmt.Errorf("invalid path %q", p)
+		}
+
+		err := fs.WalkDir(fsys, p, func(name string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.Type().IsRegular() {
+				files = append(files, name)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, http.StatusNotFound, fmt.Errorf("path not found %q", p)
+		}
+	}
+	return files, http.StatusOK, nil
+}
+
+func attachmentDisposition(filename string) string {
+	return mime.FormatMediaType("attachment", map[string]string{"filename": filename})
+}
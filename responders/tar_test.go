@@ -0,0 +1,90 @@
+package responders_test
+
+import (
+	"archive/tar"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/elmq0022/kami/responders"
+)
+
+var archiveFS = fstest.MapFS{
+	"readme.txt":        {Data: []byte("read me")},
+	"docs/guide.md":     {Data: []byte("# guide")},
+	"docs/api/index.md": {Data: []byte("# api")},
+	"secret.txt":        {Data: []byte("do not ship")},
+}
+
+func TestTarResponder(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/download", nil)
+	responders.Tar(archiveFS, []string{"readme.txt", "docs"}, "bundle.tar").Respond(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	if got := w.Header().Get("Content-Type"); got != "application/x-tar" {
+		t.Errorf("expected Content-Type %q, got %q", "application/x-tar", got)
+	}
+
+	if got := w.Header().Get("Content-Disposition"); got != `attachment; filename=bundle.tar` {
+		t.Errorf("expected Content-Disposition %q, got %q", `attachment; filename=bundle.tar`, got)
+	}
+
+	got := map[string]string{}
+	tr := tar.NewReader(w.Body)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("failed to read tar: %v", err)
+		}
+		data, _ := io.ReadAll(tr)
+		got[hdr.Name] = string(data)
+	}
+
+	want := map[string]string{
+		"readme.txt":        "read me",
+		"docs/guide.md":     "# guide",
+		"docs/api/index.md": "# api",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected entries %v, got %v", want, got)
+	}
+	for name, data := range want {
+		if got[name] != data {
+			t.Errorf("entry %q: expected %q, got %q", name, data, got[name])
+		}
+	}
+}
+
+func TestTarResponder_InvalidPaths(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+	}{
+		{name: "parent traversal", path: "../etc/passwd", wantStatus: http.StatusBadRequest},
+		{name: "nested traversal", path: "docs/../../secret.txt", wantStatus: http.StatusBadRequest},
+		{name: "absolute path", path: "/readme.txt", wantStatus: http.StatusBadRequest},
+		{name: "missing file", path: "missing.txt", wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/download", nil)
+			responders.Tar(archiveFS, []string{tt.path}, "bundle.tar").Respond(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package responders
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type tooManyRequestsResponder struct {
+	retryAfter time.Duration
+	detail     string
+}
+
+// TooManyRequests creates a responder for rate-limited requests.
+// It writes 429 with an RFC 7807 problem+json body and advertises when to retry
+// both in the Retry-After header and in the body's "retryAfter" field.
+// The delay is reported in whole seconds, rounded up.
+func TooManyRequests(retryAfter time.Duration, detail string) *tooManyRequestsResponder {
+	return &tooManyRequestsResponder{retryAfter: retryAfter, detail: detail}
+}
+
+type tooManyRequestsProblem struct {
+	Title      string `json:"title"`
+	Status     int    `json:"status"`
+	Detail     string `json:"detail,omitempty"`
+	RetryAfter int    `json:"retryAfter"`
+}
+
+// Respond writes the Retry-After header and the problem body.
+// Panics if marshaling fails, which will be caught by the router's panic recovery.
+func (t *tooManyRequestsResponder) Respond(w http.ResponseWriter, req *http.Request) {
+	seconds := int(math.Ceil(t.retryAfter.Seconds()))
+	if seconds < 0 {
+		seconds = 0
+	}
+
+	data, err := MarshalJSON(tooManyRequestsProblem{
+		Title:      http.StatusText(http.StatusTooManyRequests),
+		Status:     http.StatusTooManyRequests,
+		Detail:     t.detail,
+		RetryAfter: seconds,
+	})
+	if err != nil {
+		panic(fmt.Sprintf("failed to marshal JSON error response: %v", err))
+	}
+
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	writeBuffered(w, "application/problem+json", http.StatusTooManyRequests, data)
+}
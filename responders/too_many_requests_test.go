@@ -0,0 +1,59 @@
+package responders_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/elmq0022/kami/responders"
+)
+
+func TestTooManyRequestsResponder(t *testing.T) {
+	tests := []struct {
+		name           string
+		retryAfter     time.Duration
+		detail         string
+		expectedHeader string
+		expectedBody   string
+	}{
+		{
+			name:           "whole seconds",
+			retryAfter:     30 * time.Second,
+			detail:         "slow down",
+			expectedHeader: "30",
+			expectedBody:   `{"title":"Too Many Requests","status":429,"detail":"slow down","retryAfter":30}`,
+		},
+		{
+			name:           "rounded up",
+			retryAfter:     1500 * time.Millisecond,
+			detail:         "",
+			expectedHeader: "2",
+			expectedBody:   `{"title":"Too Many Requests","status":429,"retryAfter":2}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			responders.TooManyRequests(tt.retryAfter, tt.detail).Respond(w, r)
+
+			if w.Code != http.StatusTooManyRequests {
+				t.Errorf("expected status %d, got %d", http.StatusTooManyRequests, w.Code)
+			}
+
+			if got := w.Header().Get("Content-Type"); got != "application/problem+json" {
+				t.Errorf("expected Content-Type %q, got %q", "application/problem+json", got)
+			}
+
+			if got := w.Header().Get("Retry-After"); got != tt.expectedHeader {
+				t.Errorf("expected Retry-After %q, got %q", tt.expectedHeader, got)
+			}
+
+			if got := w.Body.String(); got != tt.expectedBody {
+				t.Errorf("expected body %q, got %q", tt.expectedBody, got)
+			}
+		})
+	}
+}
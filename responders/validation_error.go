@@ -0,0 +1,47 @@
+package responders
+
+import (
+	"fmt"
+	"net/http"
+)
+
+type validationErrorResponder struct {
+	errors map[string][]string
+}
+
+// ValidationErrorResponse creates a responder for requests that failed validation.
+// It writes 422 Unprocessable Entity with an RFC 7807 problem+json body of the form
+//
+//	{"title":"Unprocessable Entity","status":422,"errors":{"email":["is required"]}}
+//
+// where "errors" maps each invalid field to its messages. binding.ValidationErrors
+// can be passed directly.
+func ValidationErrorResponse(errors map[string][]string) *validationErrorResponder {
+	return &validationErrorResponder{errors: errors}
+}
+
+type validationProblem struct {
+	Title  string              `json:"title"`
+	Status int                 `json:"status"`
+	Errors map[string][]string `json:"errors"`
+}
+
+// Respond writes the problem body.
+// Panics if marshaling fails, which will be caught by the router's panic recovery.
+func (v *validationErrorResponder) Respond(w http.ResponseWriter, req *http.Request) {
+	errs := v.errors
+	if errs == nil {
+		errs = map[string][]string{}
+	}
+
+	data, err := MarshalJSON(validationProblem{
+		Title:  http.StatusText(http.StatusUnprocessableEntity),
+		Status: http.StatusUnprocessableEntity,
+		Errors: errs,
+	})
+	if err != nil {
+		panic(fmt.Sprintf("failed to marshal JSON error response: %v", err))
+	}
+
+	writeBuffered(w, "application/problem+json", http.StatusUnprocessableEntity, data)
+}
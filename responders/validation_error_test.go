@@ -0,0 +1,41 @@
+package responders_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/elmq0022/kami/responders"
+)
+
+func TestValidationErrorResponse(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/signup", nil)
+	responders.ValidationErrorResponse(map[string][]string{
+		"email": {"is required"},
+		"age":   {"must be a number", "must be positive"},
+	}).Respond(w, r)
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected status %d, got %d", http.StatusUnprocessableEntity, w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/problem+json" {
+		t.Errorf("expected Content-Type application/problem+json, got %q", ct)
+	}
+
+	want := `{"title":"Unprocessable Entity","status":422,"errors":{"age":["must be a number","must be positive"],"email":["is required"]}}`
+	if w.Body.String() != want {
+		t.Errorf("expected body %s, got %s", want, w.Body.String())
+	}
+}
+
+func TestValidationErrorResponse_NilErrors(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/signup", nil)
+	responders.ValidationErrorResponse(nil).Respond(w, r)
+
+	want := `{"title":"Unprocessable Entity","status":422,"errors":{}}`
+	if w.Body.String() != want {
+		t.Errorf("expected body %s, got %s", want, w.Body.String())
+	}
+}
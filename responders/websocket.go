@@ -0,0 +1,80 @@
+package responders
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// websocketGUID is the fixed GUID from RFC 6455 section 1.3 used to compute Sec-WebSocket-Accept.
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+type websocketResponder struct {
+	handler func(conn net.Conn, rw *bufio.ReadWriter)
+}
+
+// WebSocketResponse creates a responder that upgrades the request to a WebSocket connection.
+// It validates the client's opening handshake, hijacks the connection, replies with
+// 101 Switching Protocols per RFC 6455, and then hands the raw connection to handler.
+// Framing is left to the handler. The connection is closed when handler returns.
+func WebSocketResponse(handler func(conn net.Conn, rw *bufio.ReadWriter)) *websocketResponder {
+	return &websocketResponder{handler: handler}
+}
+
+// Respond performs the WebSocket handshake and runs the handler on the hijacked connection.
+// Invalid handshakes receive 400 Bad Request, and writers that do not support
+// hijacking receive 500 Internal Server Error.
+func (ws *websocketResponder) Respond(w http.ResponseWriter, req *http.Request) {
+	key := req.Header.Get("Sec-WebSocket-Key")
+	if !headerContainsToken(req.Header, "Connection", "upgrade") ||
+		!headerContainsToken(req.Header, "Upgrade", "websocket") ||
+		req.Header.Get("Sec-WebSocket-Version") != "13" ||
+		key == "" {
+		http.Error(w, "invalid websocket handshake", http.StatusBadRequest)
+		return
+	}
+
+	// The ResponseController finds the Hijacker through writers wrapped by middleware
+	conn, rw, err := http.NewResponseController(w).Hijack()
+	if errors.Is(err, http.ErrNotSupported) {
+		http.Error(w, "websocket upgrade not supported", http.StatusInternalServerError)
+		return
+	}
+	if err != nil {
+		http.Error(w, "websocket upgrade failed", http.StatusInternalServerError)
+		return
+	}
+	defer conn.Close()
+
+	rw.WriteString("HTTP/1.1 101 Switching Protocols\r\n")
+	rw.WriteString("Upgrade: websocket\r\n")
+	rw.WriteString("Connection: Upgrade\r\n")
+	rw.WriteString("Sec-WebSocket-Accept: " + websocketAccept(key) + "\r\n\r\n")
+	if err := rw.Flush(); err != nil {
+		log.Printf("websocket handshake %s: %v", req.URL.Path, err)
+		return
+	}
+
+	ws.handler(conn, rw)
+}
+
+func websocketAccept(key string) string {
+	sum := sha1.Sum([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+func headerContainsToken(h http.Header, name, token string) bool {
+	for _, v := range h.Values(name) {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
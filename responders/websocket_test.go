@@ -0,0 +1,91 @@
+package responders_test
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/elmq0022/kami/responders"
+)
+
+type hijackRecorder struct {
+	*httptest.ResponseRecorder
+	conn net.Conn
+}
+
+func (h *hijackRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return h.conn, bufio.NewReadWriter(bufio.NewReader(h.conn), bufio.NewWriter(h.conn)), nil
+}
+
+func newWebSocketRequest() *http.Request {
+	r := httptest.NewRequest(http.MethodGet, "/ws", nil)
+	r.Header.Set("Connection", "keep-alive, Upgrade")
+	r.Header.Set("Upgrade", "websocket")
+	r.Header.Set("Sec-WebSocket-Version", "13")
+	r.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	return r
+}
+
+func TestWebSocketResponder_Handshake(t *testing.T) {
+	server, client := net.Pipe()
+	defer client.Close()
+
+	handled := make(chan string, 1)
+	responder := responders.WebSocketResponse(func(conn net.Conn, rw *bufio.ReadWriter) {
+		line, _ := rw.ReadString('\n')
+		handled <- line
+	})
+
+	w := &hijackRecorder{ResponseRecorder: httptest.NewRecorder(), conn: server}
+	go responder.Respond(w, newWebSocketRequest())
+
+	br := bufio.NewReader(client)
+	resp, err := http.ReadResponse(br, nil)
+	if err != nil {
+		t.Fatalf("failed to read handshake response: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Errorf("expected status %d, got %d", http.StatusSwitchingProtocols, resp.StatusCode)
+	}
+	if got := resp.Header.Get("Upgrade"); got != "websocket" {
+		t.Errorf("expected Upgrade %q, got %q", "websocket", got)
+	}
+	if got := resp.Header.Get("Connection"); got != "Upgrade" {
+		t.Errorf("expected Connection %q, got %q", "Upgrade", got)
+	}
+	// Example key and accept value from RFC 6455 section 1.3
+	if got := resp.Header.Get("Sec-WebSocket-Accept"); got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Errorf("expected Sec-WebSocket-Accept %q, got %q", "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", got)
+	}
+
+	client.Write([]byte("ping\n"))
+	if got := <-handled; got != "ping\n" {
+		t.Errorf("expected handler to read %q, got %q", "ping\n", got)
+	}
+}
+
+func TestWebSocketResponder_Errors(t *testing.T) {
+	noop := func(conn net.Conn, rw *bufio.ReadWriter) {}
+
+	t.Run("hijack not supported", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		responders.WebSocketResponse(noop).Respond(w, newWebSocketRequest())
+
+		if w.Code != http.StatusInternalServerError {
+			t.Errorf("expected status %d, got %d", http.StatusInternalServerError, w.Code)
+		}
+	})
+
+	t.Run("missing upgrade headers", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/ws", nil)
+		responders.WebSocketResponse(noop).Respond(w, r)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+		}
+	})
+}
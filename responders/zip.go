@@ -0,0 +1,76 @@
+package responders
+
+import (
+	"archive/zip"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+)
+
+type zipResponder struct {
+	fsys     fs.FS
+	paths    []string
+	filename string
+}
+
+// Zip creates a responder that streams a zip archive of the given paths from fsys as a download.
+// Directories are included recursively. Paths must be valid fs.FS paths, so absolute paths and
+// ".." traversal are rejected with 400 Bad Request; missing paths produce 404 Not Found.
+// Entries are compressed and written one at a time rather than buffered in memory.
+func Zip(fsys fs.FS, paths []string, filename string) *zipResponder {
+	return &zipResponder{fsys: fsys, paths: paths, filename: filename}
+}
+
+// Respond validates the paths, then writes the archive with Content-Type "application/zip".
+// Errors after the first byte has been written can only be logged.
+func (z *zipResponder) Respond(w http.ResponseWriter, req *http.Request) {
+	files, status, err := archiveFiles(z.fsys, z.paths)
+	if err != nil {
+		JSONErrorResponse(err.Error(), status).Respond(w, req)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", attachmentDisposition(z.filename))
+	w.WriteHeader(http.StatusOK)
+
+	zw := zip.NewWriter(w)
+	for _, name := range files {
+		if err := writeZipEntry(zw, z.fsys, name); err != nil {
+			log.Printf("zip %s: %v", name, err)
+			return
+		}
+	}
+	if err := zw.Close(); err != nil {
+		log.Printf("zip: %v", err)
+	}
+}
+
+func writeZipEntry(zw *zip.Writer, fsys fs.FS, name string) error {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	hdr, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return err
+	}
+	hdr.Name = name
+	hdr.Method = zip.Deflate
+
+	entry, err := zw.CreateHeader(hdr)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(entry, f)
+	return err
+}
@@ -0,0 +1,71 @@
+package responders_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/elmq0022/kami/responders"
+)
+
+func TestZipResponder(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/download", nil)
+	responders.Zip(archiveFS, []string{"readme.txt", "docs"}, "bundle.zip").Respond(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	if got := w.Header().Get("Content-Type"); got != "application/zip" {
+		t.Errorf("expected Content-Type %q, got %q", "application/zip", got)
+	}
+
+	if got := w.Header().Get("Content-Disposition"); got != `attachment; filename=bundle.zip` {
+		t.Errorf("expected Content-Disposition %q, got %q", `attachment; filename=bundle.zip`, got)
+	}
+
+	body := w.Body.Bytes()
+	zr, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		t.Fatalf("failed to read zip: %v", err)
+	}
+
+	got := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("failed to open entry %q: %v", f.Name, err)
+		}
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		got[f.Name] = string(data)
+	}
+
+	want := map[string]string{
+		"readme.txt":        "read me",
+		"docs/guide.md":     "# guide",
+		"docs/api/index.md": "# api",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected entries %v, got %v", want, got)
+	}
+	for name, data := range want {
+		if got[name] != data {
+			t.Errorf("entry %q: expected %q, got %q", name, data, got[name])
+		}
+	}
+}
+
+func TestZipResponder_Traversal(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/download", nil)
+	responders.Zip(archiveFS, []string{"../secret.txt"}, "bundle.zip").Respond(w, r)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
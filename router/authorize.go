@@ -0,0 +1,27 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/elmq0022/kami/responders"
+	"github.com/elmq0022/kami/types"
+)
+
+// Policy decides whether a request may reach a route.
+type Policy func(req *http.Request) bool
+
+// Authorize returns a new router whose routes are guarded by the given policy.
+// Requests the policy denies receive a 403 Forbidden JSON error and never reach the handler.
+// Like Use, it is scoped to routes registered through the returned router:
+//
+//	r.Prefix("/admin").Authorize(isAdmin).GET(dashboard)
+func (r *Router) Authorize(policy Policy) *Router {
+	return r.Use(func(next types.Handler) types.Handler {
+		return func(req *http.Request) types.Responder {
+			if !policy(req) {
+				return responders.JSONErrorResponse(http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			}
+			return next(req)
+		}
+	})
+}
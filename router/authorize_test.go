@@ -0,0 +1,47 @@
+package router_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/elmq0022/kami/router"
+)
+
+func TestAuthorize(t *testing.T) {
+	isAdmin := func(req *http.Request) bool {
+		return req.Header.Get("X-Role") == "admin"
+	}
+
+	r, _ := router.New()
+	r.Prefix("/admin").Authorize(isAdmin).GET(NewTestHandler(http.StatusOK, "dashboard"))
+	r.Prefix("/public").GET(NewTestHandler(http.StatusOK, "public"))
+
+	tests := []struct {
+		name       string
+		path       string
+		role       string
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "policy allows", path: "/admin", role: "admin", wantStatus: http.StatusOK, wantBody: "dashboard"},
+		{name: "policy denies", path: "/admin", role: "guest", wantStatus: http.StatusForbidden, wantBody: `{"msg":"Forbidden"}`},
+		{name: "sibling unaffected", path: "/public", role: "guest", wantStatus: http.StatusOK, wantBody: "public"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set("X-Role", tt.role)
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("want %d, got %d", tt.wantStatus, rr.Code)
+			}
+			if rr.Body.String() != tt.wantBody {
+				t.Fatalf("want %q, got %q", tt.wantBody, rr.Body.String())
+			}
+		})
+	}
+}
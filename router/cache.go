@@ -0,0 +1,233 @@
+package router
+
+import (
+	"bytes"
+	"container/list"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/elmq0022/kami/types"
+)
+
+// DefaultCacheEntries is the number of responses Cache keeps when CacheConfig.MaxEntries is not set.
+const DefaultCacheEntries = 1024
+
+// CacheConfig configures the Cache middleware.
+type CacheConfig struct {
+	// MaxEntries bounds the number of cached responses; the least recently used entry
+	// is evicted once it is exceeded. Defaults to DefaultCacheEntries.
+	MaxEntries int
+	// Vary lists request headers whose values are part of the cache key, such as
+	// "Accept" or "Accept-Language", so differing representations are cached separately.
+	Vary []string
+}
+
+type cacheEntry struct {
+	key     string
+	status  int
+	header  http.Header
+	body    []byte
+	expires time.Time
+}
+
+type responseCache struct {
+	mu      sync.Mutex
+	max     int
+	entries map[string]*list.Element
+	lru     *list.List
+}
+
+func (c *responseCache) get(key string, now time.Time) (*cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*cacheEntry)
+	if !now.Before(e.expires) {
+		c.lru.Remove(el)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.lru.MoveToFront(el)
+	return e, true
+}
+
+func (c *responseCache) put(e *cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[e.key]; ok {
+		el.Value = e
+		c.lru.MoveToFront(el)
+		return
+	}
+	c.entries[e.key] = c.lru.PushFront(e)
+	for c.lru.Len() > c.max {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// Cache returns a middleware that keeps GET responses in memory for ttl and replays them
+// without calling the handler. Responses are keyed by path, query and the request headers
+// listed in cfg.Vary. Only 200 OK responses are stored, and other methods pass through untouched.
+// Responses marked Cache-Control private or no-store, responses that set cookies, and responses
+// whose Vary header names request headers missing from cfg.Vary, or is "*", are never stored. Only headers set by the cached handler are replayed; headers added by middleware
+// registered before Cache, such as Session cookies or CORS headers, are left to that middleware.
+func Cache(ttl time.Duration, cfg CacheConfig) types.Middleware {
+	if cfg.MaxEntries <= 0 {
+		cfg.MaxEntries = DefaultCacheEntries
+	}
+	cache := &responseCache{
+		max:     cfg.MaxEntries,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+
+	return func(next types.Handler) types.Handler {
+		return func(req *http.Request) types.Responder {
+			// Upgraded connections are hijacked and have no response to store
+			if req.Method != http.MethodGet || req.Header.Get("Upgrade") != "" {
+				return next(req)
+			}
+
+			key := cacheKey(req, cfg.Vary)
+			if e, ok := cache.get(key, time.Now()); ok {
+				return &cachedResponder{entry: e}
+			}
+			return &cachingResponder{inner: next(req), cache: cache, key: key, vary: cfg.Vary, ttl: ttl}
+		}
+	}
+}
+
+func cacheKey(req *http.Request, vary []string) string {
+	var b strings.Builder
+	b.WriteString(req.Method + " " + req.URL.RequestURI())
+	for _, h := range vary {
+		b.WriteString("\n" + http.CanonicalHeaderKey(h) + ": " + strings.Join(req.Header.Values(h), ","))
+	}
+	return b.String()
+}
+
+type cachingResponder struct {
+	inner types.Responder
+	cache *responseCache
+	key   string
+	vary  []string
+	ttl   time.Duration
+}
+
+func (c *cachingResponder) Respond(w http.ResponseWriter, req *http.Request) {
+	before := w.Header().Clone()
+	cw := &cacheWriter{loggingWriter: loggingWriter{ResponseWriter: w, statusCode: http.StatusOK}}
+	c.inner.Respond(cw, req)
+
+	if cw.statusCode != http.StatusOK || !cacheable(w.Header()) || !keyedVary(before, w.Header(), c.vary) {
+		return
+	}
+	header, ok := ownHeaders(before, w.Header())
+	if !ok {
+		return
+	}
+	c.cache.put(&cacheEntry{
+		key:     c.key,
+		status:  cw.statusCode,
+		header:  header,
+		body:    cw.body.Bytes(),
+		expires: time.Now().Add(c.ttl),
+	})
+}
+
+// cacheable reports whether a response may be stored in a shared cache, which is not the
+// case when it is marked private or no-store, or varies on "*".
+func cacheable(h http.Header) bool {
+	if varyNames(h)["*"] {
+		return false
+	}
+	for _, v := range h.Values("Cache-Control") {
+		for _, directive := range strings.Split(v, ",") {
+			name, _, _ := strings.Cut(strings.TrimSpace(directive), "=")
+			if strings.EqualFold(name, "private") || strings.EqualFold(name, "no-store") {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// keyedVary reports whether every request header named in a Vary header added by the cached
+// handler is part of the cache key, so a negotiated response is only replayed to requests
+// that would have negotiated the same representation.
+func keyedVary(before, after http.Header, keyed []string) bool {
+	prior := varyNames(before)
+	for name := range varyNames(after) {
+		if prior[name] {
+			continue
+		}
+		if !slices.ContainsFunc(keyed, func(k string) bool { return http.CanonicalHeaderKey(k) == name }) {
+			return false
+		}
+	}
+	return true
+}
+
+// varyNames returns the canonical header names listed in h's Vary headers.
+func varyNames(h http.Header) map[string]bool {
+	names := make(map[string]bool)
+	for _, v := range h.Values("Vary") {
+		for _, name := range strings.Split(v, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names[http.CanonicalHeaderKey(name)] = true
+			}
+		}
+	}
+	return names
+}
+
+// ownHeaders returns the headers the cached responder added or changed, leaving out those that
+// outer middleware set before it ran, since they belong to that one request and must not be
+// replayed to other clients. A response that sets a cookie itself is not cached at all.
+func ownHeaders(before, after http.Header) (http.Header, bool) {
+	if len(after.Values("Set-Cookie")) > len(before.Values("Set-Cookie")) {
+		return nil, false
+	}
+
+	own := make(http.Header)
+	for k, v := range after {
+		if k == "Set-Cookie" || slices.Equal(before[k], v) {
+			continue
+		}
+		own[k] = append([]string(nil), v...)
+	}
+	return own, true
+}
+
+// cacheWriter records the body as it is written through to the client.
+type cacheWriter struct {
+	loggingWriter
+	body bytes.Buffer
+}
+
+func (cw *cacheWriter) Write(p []byte) (int, error) {
+	cw.body.Write(p)
+	return cw.ResponseWriter.Write(p)
+}
+
+type cachedResponder struct {
+	entry *cacheEntry
+}
+
+func (c *cachedResponder) Respond(w http.ResponseWriter, req *http.Request) {
+	for k, v := range c.entry.header {
+		w.Header()[k] = append([]string(nil), v...)
+	}
+	w.WriteHeader(c.entry.status)
+	w.Write(c.entry.body)
+}
@@ -0,0 +1,258 @@
+package router_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elmq0022/kami/responders"
+	"github.com/elmq0022/kami/router"
+	"github.com/elmq0022/kami/types"
+)
+
+func newCachedRouter(t *testing.T, ttl time.Duration, cfg router.CacheConfig) (*router.Router, *int) {
+	t.Helper()
+	r, err := router.New()
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+
+	calls := 0
+	r.Use(router.Cache(ttl, cfg)).Prefix("/report").GET(func(req *http.Request) types.Responder {
+		calls++
+		return responders.JSONResponse(fmt.Sprintf("%s #%d", req.Header.Get("Accept-Language"), calls), http.StatusOK)
+	})
+	return r, &calls
+}
+
+func getReport(r *router.Router, lang string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/report", nil)
+	if lang != "" {
+		req.Header.Set("Accept-Language", lang)
+	}
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestCache_MissThenHit(t *testing.T) {
+	r, calls := newCachedRouter(t, time.Minute, router.CacheConfig{})
+
+	first := getReport(r, "")
+	second := getReport(r, "")
+
+	if *calls != 1 {
+		t.Fatalf("expected handler to run once, ran %d times", *calls)
+	}
+	if second.Code != http.StatusOK || second.Body.String() != first.Body.String() {
+		t.Errorf("expected cached %q, got %d %q", first.Body.String(), second.Code, second.Body.String())
+	}
+	if ct := second.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("expected cached Content-Type header, got %q", ct)
+	}
+}
+
+func TestCache_Expiry(t *testing.T) {
+	r, calls := newCachedRouter(t, 20*time.Millisecond, router.CacheConfig{})
+
+	getReport(r, "")
+	time.Sleep(40 * time.Millisecond)
+	w := getReport(r, "")
+
+	if *calls != 2 {
+		t.Fatalf("expected handler to run again after expiry, ran %d times", *calls)
+	}
+	if w.Body.String() != `" #2"` {
+		t.Errorf("expected fresh body, got %q", w.Body.String())
+	}
+}
+
+func TestCache_Vary(t *testing.T) {
+	r, calls := newCachedRouter(t, time.Minute, router.CacheConfig{Vary: []string{"Accept-Language"}})
+
+	en := getReport(r, "en")
+	de := getReport(r, "de")
+	enAgain := getReport(r, "en")
+
+	if *calls != 2 {
+		t.Fatalf("expected one handler call per language, got %d", *calls)
+	}
+	if en.Body.String() == de.Body.String() {
+		t.Errorf("expected distinct entries per Accept-Language, both were %q", en.Body.String())
+	}
+	if enAgain.Body.String() != en.Body.String() {
+		t.Errorf("expected cached %q, got %q", en.Body.String(), enAgain.Body.String())
+	}
+}
+
+func TestCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	r, calls := newCachedRouter(t, time.Minute, router.CacheConfig{MaxEntries: 2, Vary: []string{"Accept-Language"}})
+
+	getReport(r, "en")
+	getReport(r, "de")
+	getReport(r, "en") // hit, en becomes most recently used
+	getReport(r, "fr") // evicts de
+	getReport(r, "en") // still cached
+	getReport(r, "de") // miss
+
+	if *calls != 4 {
+		t.Errorf("expected 4 handler calls, got %d", *calls)
+	}
+}
+
+func TestCache_SkipsNonGET(t *testing.T) {
+	r, err := router.New()
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+	calls := 0
+	r.Use(router.Cache(time.Minute, router.CacheConfig{})).Prefix("/items").POST(func(req *http.Request) types.Responder {
+		calls++
+		return &testResponder{Status: http.StatusOK, Body: "created"}
+	})
+
+	for i := 0; i < 2; i++ {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/items", nil))
+	}
+	if calls != 2 {
+		t.Errorf("expected POST to bypass the cache, handler ran %d times", calls)
+	}
+}
+
+func TestCache_DoesNotReplayOuterSessionCookie(t *testing.T) {
+	r, err := router.New()
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+	store := router.NewMemorySessionStore(time.Minute)
+	r.Use(router.Session(store, router.SessionConfig{})).Use(router.Cache(time.Minute, router.CacheConfig{})).Prefix("/report").GET(func(req *http.Request) types.Responder {
+		router.GetSession(req.Context()).Set("seen", true)
+		return responders.JSONResponse("report", http.StatusOK)
+	})
+
+	first := getReport(r, "")
+	firstCookies := first.Result().Cookies()
+	if len(firstCookies) != 1 {
+		t.Fatalf("expected the first client to get a session cookie, got %v", firstCookies)
+	}
+
+	second := getReport(r, "")
+	if second.Body.String() != first.Body.String() {
+		t.Fatalf("expected a cache hit, got %q", second.Body.String())
+	}
+	for _, c := range second.Result().Cookies() {
+		if c.Value == firstCookies[0].Value {
+			t.Fatalf("second client received the first client's session ID %q", c.Value)
+		}
+	}
+}
+
+func TestCache_SkipsOwnSetCookieAndPrivate(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		value  string
+	}{
+		{"set-cookie", "Set-Cookie", "id=1"},
+		{"private", "Cache-Control", "private, max-age=60"},
+		{"no-store", "Cache-Control", "no-store"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := router.New()
+			if err != nil {
+				t.Fatalf("failed to create router: %v", err)
+			}
+			calls := 0
+			r.Use(router.Cache(time.Minute, router.CacheConfig{})).Prefix("/report").GET(func(req *http.Request) types.Responder {
+				calls++
+				return &cacheHeaderResponder{key: tt.header, value: tt.value}
+			})
+
+			getReport(r, "")
+			getReport(r, "")
+			if calls != 2 {
+				t.Errorf("expected the response not to be cached, handler ran %d times", calls)
+			}
+		})
+	}
+}
+
+type cacheHeaderResponder struct {
+	key, value string
+}
+
+func (h *cacheHeaderResponder) Respond(w http.ResponseWriter, req *http.Request) {
+	w.Header().Add(h.key, h.value)
+	w.WriteHeader(http.StatusOK)
+}
+
+type report struct {
+	N int `json:"n" xml:"n"`
+}
+
+func TestCache_SkipsResponsesVaryingOnUnkeyedHeaders(t *testing.T) {
+	tests := []struct {
+		name       string
+		cfg        router.CacheConfig
+		wantCached bool
+	}{
+		{"vary header not keyed", router.CacheConfig{}, false},
+		{"vary header keyed", router.CacheConfig{Vary: []string{"accept"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := router.New()
+			if err != nil {
+				t.Fatalf("failed to create router: %v", err)
+			}
+			calls := 0
+			r.Use(router.Cache(time.Minute, tt.cfg)).Prefix("/report").GET(func(req *http.Request) types.Responder {
+				calls++
+				return responders.Negotiate(req, report{N: calls}, http.StatusOK)
+			})
+
+			get := func(accept string) *httptest.ResponseRecorder {
+				w := httptest.NewRecorder()
+				req := httptest.NewRequest(http.MethodGet, "/report", nil)
+				req.Header.Set("Accept", accept)
+				r.ServeHTTP(w, req)
+				return w
+			}
+
+			get("application/json")
+			xml := get("application/xml")
+			if ct := xml.Header().Get("Content-Type"); !strings.Contains(ct, "xml") {
+				t.Errorf("expected the XML client to get XML, got %q", ct)
+			}
+			get("application/json")
+
+			if cached := calls < 3; cached != tt.wantCached {
+				t.Errorf("expected cached=%v, handler ran %d times", tt.wantCached, calls)
+			}
+		})
+	}
+}
+
+func TestCache_SkipsVaryStar(t *testing.T) {
+	r, err := router.New()
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+	calls := 0
+	r.Use(router.Cache(time.Minute, router.CacheConfig{})).Prefix("/report").GET(func(req *http.Request) types.Responder {
+		calls++
+		return &cacheHeaderResponder{key: "Vary", value: "*"}
+	})
+
+	getReport(r, "")
+	getReport(r, "")
+	if calls != 2 {
+		t.Errorf("expected Vary: * not to be cached, handler ran %d times", calls)
+	}
+}
@@ -0,0 +1,77 @@
+package router
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/elmq0022/kami/types"
+)
+
+// WithCleanPath normalizes request paths before routing: repeated slashes are collapsed and
+// "." and ".." segments are resolved without escaping the root, so /api//v1/./users reaches
+// /api/v1/users. A trailing slash is kept and percent-encoded bytes (such as %2F inside a
+// wildcard capture) are left as sent. When redirect is set, GET and HEAD requests for an
+// unclean path receive a 301 to the cleaned URL; other methods, or all methods when redirect
+// is not set, are routed internally to the cleaned path. It runs before any other pre-route middleware.
+func WithCleanPath(redirect bool) Option {
+	return func(r *Router) {
+		*r.preRoute = append([]types.Middleware{cleanPath(redirect)}, *r.preRoute...)
+	}
+}
+
+func cleanPath(redirect bool) types.Middleware {
+	return func(next types.Handler) types.Handler {
+		return func(req *http.Request) types.Responder {
+			escaped := req.URL.EscapedPath()
+			cleaned := cleanURLPath(escaped)
+			if cleaned == escaped {
+				return next(req)
+			}
+
+			if redirect && (req.Method == http.MethodGet || req.Method == http.MethodHead) {
+				target := &url.URL{RawPath: cleaned, RawQuery: req.URL.RawQuery}
+				target.Path, _ = url.PathUnescape(cleaned)
+				return &redirectResponder{url: target.String(), status: http.StatusMovedPermanently}
+			}
+
+			unescaped, err := url.PathUnescape(cleaned)
+			if err != nil {
+				return next(req)
+			}
+
+			r2 := new(http.Request)
+			*r2 = *req
+			r2.URL = new(url.URL)
+			*r2.URL = *req.URL
+			r2.URL.Path = unescaped
+			r2.URL.RawPath = ""
+			if unescaped != cleaned {
+				r2.URL.RawPath = cleaned
+			}
+			return next(r2)
+		}
+	}
+}
+
+// cleanURLPath applies path.Clean to an escaped path, keeping any trailing slash.
+func cleanURLPath(p string) string {
+	if p == "" {
+		return "/"
+	}
+	cleaned := path.Clean("/" + p)
+	if strings.HasSuffix(p, "/") && cleaned != "/" {
+		cleaned += "/"
+	}
+	return cleaned
+}
+
+type redirectResponder struct {
+	url    string
+	status int
+}
+
+func (rr *redirectResponder) Respond(w http.ResponseWriter, req *http.Request) {
+	http.Redirect(w, req, rr.url, rr.status)
+}
@@ -0,0 +1,90 @@
+package router_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/elmq0022/kami/router"
+	"github.com/elmq0022/kami/types"
+)
+
+func newCleanPathRouter(t *testing.T, redirect bool) *router.Router {
+	t.Helper()
+	r, err := router.New(router.WithCleanPath(redirect))
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+	r.Prefix("/api/v1/users").GET(NewTestHandler(http.StatusOK, "users"))
+	r.Prefix("/api/v1/users").POST(NewTestHandler(http.StatusCreated, "created"))
+	r.Prefix("/files/*path").GET(func(req *http.Request) types.Responder {
+		return &testResponder{Status: http.StatusOK, Body: req.URL.EscapedPath()}
+	})
+	return r
+}
+
+func TestWithCleanPath_Route(t *testing.T) {
+	r := newCleanPathRouter(t, false)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "duplicate slashes", method: http.MethodGet, path: "/api//v1///users", wantStatus: http.StatusOK, wantBody: "users"},
+		{name: "dot segments", method: http.MethodGet, path: "/api/v1/./admin/../users", wantStatus: http.StatusOK, wantBody: "users"},
+		{name: "dot dot above root", method: http.MethodGet, path: "/../../api/v1/users", wantStatus: http.StatusOK, wantBody: "users"},
+		{name: "clean path untouched", method: http.MethodGet, path: "/api/v1/users", wantStatus: http.StatusOK, wantBody: "users"},
+		{name: "encoded wildcard kept", method: http.MethodGet, path: "/files//a%2Fb", wantStatus: http.StatusOK, wantBody: "/files/a%2Fb"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+			if w.Body.String() != tt.wantBody {
+				t.Fatalf("expected body %q, got %q", tt.wantBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestWithCleanPath_Redirect(t *testing.T) {
+	r := newCleanPathRouter(t, true)
+
+	t.Run("GET redirects", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api//v1///users?page=2", nil))
+
+		if w.Code != http.StatusMovedPermanently {
+			t.Fatalf("expected status %d, got %d", http.StatusMovedPermanently, w.Code)
+		}
+		if loc := w.Header().Get("Location"); loc != "/api/v1/users?page=2" {
+			t.Fatalf("expected Location %q, got %q", "/api/v1/users?page=2", loc)
+		}
+	})
+
+	t.Run("POST routes internally", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api//v1/users", nil))
+
+		if w.Code != http.StatusCreated {
+			t.Fatalf("expected status %d, got %d", http.StatusCreated, w.Code)
+		}
+	})
+
+	t.Run("clean path passes through", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/users", nil))
+
+		if w.Code != http.StatusOK || w.Body.String() != "users" {
+			t.Fatalf("expected 200 users, got %d %q", w.Code, w.Body.String())
+		}
+	})
+}
@@ -0,0 +1,28 @@
+package router
+
+// ConfigSnapshot is a serializable summary of a router's configuration,
+// suitable for exposing on a diagnostics endpoint.
+type ConfigSnapshot struct {
+	Prefix           string   `json:"prefix"`
+	CustomNotFound   bool     `json:"customNotFound"`
+	Middleware       int      `json:"middleware"`
+	GlobalMiddleware int      `json:"globalMiddleware"`
+	Routes           int      `json:"routes"`
+	StaticMounts     []string `json:"staticMounts"`
+	Started          bool     `json:"started"`
+}
+
+// Config returns a snapshot of the router's configuration.
+// Middleware counts the route-scoped middleware accumulated by this router through Use,
+// while Routes and StaticMounts describe the whole shared route tree.
+func (r *Router) Config() ConfigSnapshot {
+	return ConfigSnapshot{
+		Prefix:           r.prefix,
+		CustomNotFound:   r.customNotFound,
+		Middleware:       len(r.middleware),
+		GlobalMiddleware: len(*r.preRoute),
+		Routes:           len(r.radix.Routes()),
+		StaticMounts:     append([]string{}, *r.staticMounts...),
+		Started:          r.started.Load(),
+	}
+}
@@ -0,0 +1,61 @@
+package router_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"testing"
+	"testing/fstest"
+
+	"github.com/elmq0022/kami/router"
+)
+
+func TestConfig(t *testing.T) {
+	r, err := router.New(router.WithNotFound(NewTestHandler(http.StatusNotFound, "nope")))
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+
+	r.UseGlobal(testMiddleware1)
+	api := r.Prefix("/api").Use(testMiddleware1, testMiddleware2)
+	api.Prefix("/users").GET(testHandler)
+	api.Prefix("/users").POST(testHandler)
+	r.Prefix("/static").ServeStatic(fstest.MapFS{"a.txt": {Data: []byte("a")}})
+
+	got := api.Config()
+
+	if got.Prefix != "/api" {
+		t.Errorf("prefix: want %q, got %q", "/api", got.Prefix)
+	}
+	if !got.CustomNotFound {
+		t.Error("expected CustomNotFound to be true")
+	}
+	if got.Middleware != 2 {
+		t.Errorf("middleware: want 2, got %d", got.Middleware)
+	}
+	if got.GlobalMiddleware != 1 {
+		t.Errorf("global middleware: want 1, got %d", got.GlobalMiddleware)
+	}
+	if got.Routes != 3 {
+		t.Errorf("routes: want 3, got %d", got.Routes)
+	}
+	if want := []string{"/static"}; !slices.Equal(got.StaticMounts, want) {
+		t.Errorf("static mounts: want %v, got %v", want, got.StaticMounts)
+	}
+	if got.Started {
+		t.Error("expected Started to be false before serving")
+	}
+
+	if _, err := json.Marshal(got); err != nil {
+		t.Fatalf("snapshot should be serializable: %v", err)
+	}
+}
+
+func TestConfig_Defaults(t *testing.T) {
+	r, _ := router.New()
+
+	got := r.Config()
+	if got.CustomNotFound || got.Middleware != 0 || got.GlobalMiddleware != 0 || got.Routes != 0 || len(got.StaticMounts) != 0 {
+		t.Fatalf("expected an empty snapshot, got %+v", got)
+	}
+}
@@ -0,0 +1,39 @@
+package router
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/elmq0022/kami/responders"
+	"github.com/elmq0022/kami/types"
+)
+
+// RequireContentType returns a middleware that rejects requests whose Content-Type is not one
+// of allowed with 415 Unsupported Media Type. Parameters such as charset are ignored and the
+// comparison is case-insensitive. GET and HEAD requests, and requests without a body, are exempt.
+//
+//	api := r.Use(router.RequireContentType("application/json"))
+func RequireContentType(allowed ...string) types.Middleware {
+	set := make(map[string]bool, len(allowed))
+	for _, a := range allowed {
+		set[strings.ToLower(a)] = true
+	}
+
+	return func(next types.Handler) types.Handler {
+		return func(req *http.Request) types.Responder {
+			if req.Method == http.MethodGet || req.Method == http.MethodHead || req.ContentLength == 0 {
+				return next(req)
+			}
+
+			mediaType, _, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+			if err != nil || !set[mediaType] {
+				return responders.JSONErrorResponse(
+					http.StatusText(http.StatusUnsupportedMediaType),
+					http.StatusUnsupportedMediaType,
+				)
+			}
+			return next(req)
+		}
+	}
+}
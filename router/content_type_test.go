@@ -0,0 +1,49 @@
+package router_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/elmq0022/kami/router"
+)
+
+func TestRequireContentType(t *testing.T) {
+	r, err := router.New()
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+	api := r.Use(router.RequireContentType("application/json")).Prefix("/items")
+	api.GET(NewTestHandler(http.StatusOK, "list"))
+	api.POST(NewTestHandler(http.StatusCreated, "created"))
+
+	tests := []struct {
+		name        string
+		method      string
+		contentType string
+		body        string
+		wantStatus  int
+	}{
+		{name: "matching type", method: http.MethodPost, contentType: "application/json", body: `{}`, wantStatus: http.StatusCreated},
+		{name: "matching type with charset", method: http.MethodPost, contentType: "Application/JSON; charset=utf-8", body: `{}`, wantStatus: http.StatusCreated},
+		{name: "non-matching type", method: http.MethodPost, contentType: "text/plain", body: "hi", wantStatus: http.StatusUnsupportedMediaType},
+		{name: "missing type", method: http.MethodPost, body: `{}`, wantStatus: http.StatusUnsupportedMediaType},
+		{name: "bodyless GET", method: http.MethodGet, wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/items", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+		})
+	}
+}
@@ -1,10 +1,21 @@
 package router
 
-import "context"
+import (
+	"context"
+	"net/url"
+	"strings"
+)
 
 type contextKey string
 
-const paramsKey contextKey = "paramsKey"
+const (
+	// paramsKey is versioned so a change to the params representation gets a new key
+	// and older values are ignored by GetParams rather than misread.
+	paramsKey         contextKey = "paramsKey.v1"
+	allowedMethodsKey contextKey = "allowedMethodsKey"
+	routePatternKey   contextKey = "routePatternKey"
+	wildcardKey       contextKey = "wildcardKey"
+)
 
 // WithParams adds URL parameters to the request context.
 // This is used internally by the router to store matched path parameters.
@@ -14,10 +25,103 @@ func WithParams(ctx context.Context, params map[string]string) context.Context {
 
 // GetParams extracts URL parameters from the request context.
 // Parameters come from route definitions like "/users/:id" where :id becomes a parameter.
-// Returns an empty map if no parameters are present in the context.
+// Returns an empty, non-nil map if no parameters are present in the context.
 func GetParams(ctx context.Context) map[string]string {
-	if p, ok := ctx.Value(paramsKey).(map[string]string); ok {
+	if p, ok := ctx.Value(paramsKey).(map[string]string); ok && p != nil {
 		return p
 	}
 	return make(map[string]string)
 }
+
+// WithAllowedMethods adds the methods registered for the requested path to the context.
+// This is used internally by the router when a lookup misses so the not-found
+// handler can tell a method mismatch apart from an unknown path.
+func WithAllowedMethods(ctx context.Context, methods []string) context.Context {
+	return context.WithValue(ctx, allowedMethodsKey, methods)
+}
+
+// GetAllowedMethods extracts the methods registered for the requested path from the context.
+// It is populated when the router falls back to the not-found handler.
+// Returns an empty slice for a true 404, where no method is registered for the path.
+func GetAllowedMethods(ctx context.Context) []string {
+	if m, ok := ctx.Value(allowedMethodsKey).([]string); ok {
+		return m
+	}
+	return []string{}
+}
+
+func withRoutePattern(ctx context.Context, pattern string) context.Context {
+	return context.WithValue(ctx, routePatternKey, pattern)
+}
+
+// GetRoutePattern returns the pattern of the route that matched the request, such as "/users/:id".
+// Unlike the concrete request path, the pattern has low cardinality, which makes it suitable as a metrics label.
+// Returns an empty string when no route matched or outside the router.
+func GetRoutePattern(ctx context.Context) string {
+	p, _ := ctx.Value(routePatternKey).(string)
+	return p
+}
+
+type wildcard struct {
+	name  string
+	value string
+}
+
+func withWildcard(ctx context.Context, name, value string) context.Context {
+	return context.WithValue(ctx, wildcardKey, wildcard{name: name, value: value})
+}
+
+// GetWildcard returns the name and captured value of the matched route's wildcard segment.
+// The value is the remainder of the request's escaped path exactly as sent, neither decoded
+// nor cleaned, so "/files/a%2Fb" matched by "/files/*path" yields ("path", "a%2Fb").
+// When an encoded slash splits a segment across the wildcard's start, as "/x/a%2Fb/c" matched by
+// "/x/:id/*rest", the decoded remainder "b/c" is returned instead.
+// Returns empty strings when the route has no wildcard.
+func GetWildcard(ctx context.Context) (name, value string) {
+	w, _ := ctx.Value(wildcardKey).(wildcard)
+	return w.name, w.value
+}
+
+// wildcardName returns the name of the wildcard ending pattern, if any.
+func wildcardName(pattern string) (string, bool) {
+	i := strings.LastIndexByte(pattern, '/')
+	if i < 0 || i+1 >= len(pattern) || pattern[i+1] != '*' {
+		return "", false
+	}
+	return pattern[i+2:], true
+}
+
+// rawRemainder returns what follows the first skip segments of an escaped path, unmodified.
+// Segments are counted as the route tree counts them, on the decoded path with empty segments
+// ignored, so an escaped segment such as "a%2Fb" counts as two. It reports false when the
+// remainder would start inside such a segment and so has no raw form.
+func rawRemainder(escaped string, skip int) (string, bool) {
+	rest := escaped
+	for skip > 0 {
+		rest = strings.TrimLeft(rest, "/")
+		if rest == "" {
+			return "", true
+		}
+
+		seg := rest
+		if i := strings.IndexByte(rest, '/'); i >= 0 {
+			seg, rest = rest[:i], rest[i:]
+		} else {
+			rest = ""
+		}
+
+		decoded, err := url.PathUnescape(seg)
+		if err != nil {
+			return "", false
+		}
+		for _, part := range strings.Split(decoded, "/") {
+			if part != "" {
+				skip--
+			}
+		}
+		if skip < 0 {
+			return "", false
+		}
+	}
+	return strings.TrimPrefix(rest, "/"), true
+}
@@ -3,9 +3,13 @@ package router_test
 import (
 	"context"
 	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
 	"testing"
 
 	"github.com/elmq0022/kami/router"
+	"github.com/elmq0022/kami/types"
 )
 
 func TestParamsRoundTrip(t *testing.T) {
@@ -22,3 +26,121 @@ func TestParamsRoundTrip(t *testing.T) {
 		t.Fatalf("expected empty map, got %v", empty)
 	}
 }
+
+func TestAllowedMethodsRoundTrip(t *testing.T) {
+	want := []string{"GET", "POST"}
+	ctx := router.WithAllowedMethods(context.Background(), want)
+
+	got := router.GetAllowedMethods(ctx)
+	if !slices.Equal(got, want) {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+
+	empty := router.GetAllowedMethods(context.Background())
+	if empty == nil || len(empty) != 0 {
+		t.Fatalf("expected empty slice, got %v", empty)
+	}
+}
+
+func TestGetParams_EmptyNonNil(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  context.Context
+	}{
+		{name: "no params", ctx: context.Background()},
+		{name: "nil params", ctx: router.WithParams(context.Background(), nil)},
+		{name: "unversioned key", ctx: context.WithValue(context.Background(), "paramsKey", map[string]string{"id": "1"})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := router.GetParams(tt.ctx)
+			if got == nil || len(got) != 0 {
+				t.Fatalf("expected empty non-nil map, got %v", got)
+			}
+			got["id"] = "safe to write"
+		})
+	}
+}
+
+func TestGetWildcard(t *testing.T) {
+	r, err := router.New()
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+
+	var name, value, param string
+	r.Prefix("/files/*path").GET(func(req *http.Request) types.Responder {
+		name, value = router.GetWildcard(req.Context())
+		param = router.GetParams(req.Context())["path"]
+		return NewTestHandler(http.StatusOK, "ok")(req)
+	})
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "/files/a%2Fb", want: "a%2Fb"},
+		{path: "/files/dir/my%20file.txt", want: "dir/my%20file.txt"},
+		{path: "/files/a//b/./c", want: "a//b/./c"},
+		{path: "/files", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if name != "path" {
+				t.Fatalf("expected wildcard name %q, got %q", "path", name)
+			}
+			if value != tt.want {
+				t.Fatalf("expected raw remainder %q, got %q", tt.want, value)
+			}
+			if param != tt.want {
+				t.Fatalf("expected params to hold the raw remainder %q, got %q", tt.want, param)
+			}
+		})
+	}
+}
+
+func TestGetWildcard_EscapedSlashBeforeWildcard(t *testing.T) {
+	r, err := router.New()
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+
+	var params map[string]string
+	handler := func(req *http.Request) types.Responder {
+		params = router.GetParams(req.Context())
+		return NewTestHandler(http.StatusOK, "ok")(req)
+	}
+	r.Prefix("/x/:id/*rest").GET(handler)
+	r.Prefix("/y/:a/:b/*rest").GET(handler)
+
+	tests := []struct {
+		path string
+		want map[string]string
+	}{
+		// The wildcard starts inside "a%2Fb", so the decoded remainder is kept
+		{path: "/x/a%2Fb/c", want: map[string]string{"id": "a", "rest": "b/c"}},
+		{path: "/y/a%2Fb/c%2Fd", want: map[string]string{"a": "a", "b": "b", "rest": "c%2Fd"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if !maps.Equal(params, tt.want) {
+				t.Fatalf("expected params %v, got %v", tt.want, params)
+			}
+		})
+	}
+}
+
+func TestGetWildcard_NoWildcard(t *testing.T) {
+	name, value := router.GetWildcard(context.Background())
+	if name != "" || value != "" {
+		t.Fatalf("expected empty wildcard, got %q=%q", name, value)
+	}
+}
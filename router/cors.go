@@ -0,0 +1,120 @@
+package router
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/elmq0022/kami/types"
+)
+
+// CORSConfig configures the CORS middleware.
+type CORSConfig struct {
+	// AllowedOrigins lists the origins allowed to make cross-origin requests, such as
+	// "https://app.example.com". "*" allows any origin.
+	AllowedOrigins []string
+	// AllowedMethods lists the methods allowed in preflighted requests.
+	// Defaults to GET, HEAD and POST.
+	AllowedMethods []string
+	// AllowedHeaders lists the request headers allowed in preflighted requests.
+	AllowedHeaders []string
+	// ExposedHeaders lists the response headers scripts may read.
+	ExposedHeaders []string
+	// AllowCredentials lets requests carry cookies and authorization headers.
+	// It cannot be combined with a "*" origin.
+	AllowCredentials bool
+	// MaxAge is how long browsers may cache a preflight result. Zero leaves it to the browser.
+	MaxAge time.Duration
+}
+
+// CORS returns a middleware implementing Cross-Origin Resource Sharing for the routes it wraps.
+// Because it is ordinary route middleware, different endpoints can allow different origins by
+// passing it in the per-route slot. Preflight requests are OPTIONS requests, so register the
+// middleware on an OPTIONS route for the same path as well; it answers preflights itself with
+// 204 No Content, and the route's handler only runs for plain OPTIONS requests:
+//
+//	cors := router.CORS(router.CORSConfig{AllowedOrigins: []string{"https://app.example.com"}, AllowedMethods: []string{"PUT"}})
+//	orders := r.Prefix("/orders/:id")
+//	orders.PUT(updateOrder, cors)
+//	orders.OPTIONS(func(*http.Request) types.Responder { return responders.NoContentResponse() }, cors)
+//
+// A registered OPTIONS route takes precedence over a WithGlobalOptions handler, which only sees
+// OPTIONS requests for paths without one, so global and per-route CORS can be combined.
+// Requests without an Origin header, or from an origin that is not allowed, get no CORS headers.
+// Every response carries Vary: Origin so shared caches keep them apart, unless AllowedOrigins is
+// exactly "*" and credentials are off. CORS panics if "*" is combined with AllowCredentials.
+func CORS(cfg CORSConfig) types.Middleware {
+	if len(cfg.AllowedMethods) == 0 {
+		cfg.AllowedMethods = []string{http.MethodGet, http.MethodHead, http.MethodPost}
+	}
+	anyOrigin := slices.Contains(cfg.AllowedOrigins, "*")
+	if anyOrigin && cfg.AllowCredentials {
+		panic("cors: AllowedOrigins \"*\" cannot be combined with AllowCredentials")
+	}
+	varyOrigin := !slices.Equal(cfg.AllowedOrigins, []string{"*"})
+	methods := strings.Join(cfg.AllowedMethods, ", ")
+	headers := strings.Join(cfg.AllowedHeaders, ", ")
+	exposed := strings.Join(cfg.ExposedHeaders, ", ")
+
+	return func(next types.Handler) types.Handler {
+		return func(req *http.Request) types.Responder {
+			h := http.Header{}
+			if varyOrigin {
+				h.Add("Vary", "Origin")
+			}
+			origin := req.Header.Get("Origin")
+			if origin == "" || !(anyOrigin || slices.Contains(cfg.AllowedOrigins, origin)) {
+				if len(h) == 0 {
+					return next(req)
+				}
+				return &corsResponder{inner: next(req), header: h}
+			}
+
+			if anyOrigin {
+				h.Set("Access-Control-Allow-Origin", "*")
+			} else {
+				h.Set("Access-Control-Allow-Origin", origin)
+			}
+			if cfg.AllowCredentials {
+				h.Set("Access-Control-Allow-Credentials", "true")
+			}
+
+			if req.Method == http.MethodOptions && req.Header.Get("Access-Control-Request-Method") != "" {
+				h.Set("Access-Control-Allow-Methods", methods)
+				if headers != "" {
+					h.Set("Access-Control-Allow-Headers", headers)
+				}
+				if cfg.MaxAge > 0 {
+					h.Set("Access-Control-Max-Age", strconv.Itoa(int(cfg.MaxAge.Seconds())))
+				}
+				return &corsResponder{header: h}
+			}
+
+			if exposed != "" {
+				h.Set("Access-Control-Expose-Headers", exposed)
+			}
+			return &corsResponder{inner: next(req), header: h}
+		}
+	}
+}
+
+// corsResponder adds the CORS headers and delegates to inner, or answers a preflight with 204 when inner is nil.
+type corsResponder struct {
+	inner  types.Responder
+	header http.Header
+}
+
+func (c *corsResponder) Respond(w http.ResponseWriter, req *http.Request) {
+	for k, v := range c.header {
+		for _, value := range v {
+			w.Header().Add(k, value)
+		}
+	}
+	if c.inner == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	c.inner.Respond(w, req)
+}
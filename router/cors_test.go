@@ -0,0 +1,161 @@
+package router_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/elmq0022/kami/responders"
+	"github.com/elmq0022/kami/router"
+	"github.com/elmq0022/kami/types"
+)
+
+func newCORSRouter(t *testing.T) *router.Router {
+	t.Helper()
+	r, err := router.New()
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+
+	cors := router.CORS(router.CORSConfig{
+		AllowedOrigins: []string{"https://app.example.com"},
+		AllowedMethods: []string{http.MethodGet, http.MethodPut},
+		AllowedHeaders: []string{"Content-Type"},
+		ExposedHeaders: []string{"X-Request-Id"},
+		MaxAge:         10 * time.Minute,
+	})
+	orders := r.Prefix("/orders")
+	orders.PUT(NewTestHandler(http.StatusOK, "updated"), cors)
+	orders.OPTIONS(func(*http.Request) types.Responder { return responders.NoContentResponse() }, cors)
+
+	r.Prefix("/reports").PUT(NewTestHandler(http.StatusOK, "report"))
+	return r
+}
+
+func TestCORS_Preflight(t *testing.T) {
+	r := newCORSRouter(t)
+
+	req := httptest.NewRequest(http.MethodOptions, "/orders", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPut)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected status 204, got %d", w.Code)
+	}
+	want := map[string]string{
+		"Access-Control-Allow-Origin":  "https://app.example.com",
+		"Access-Control-Allow-Methods": "GET, PUT",
+		"Access-Control-Allow-Headers": "Content-Type",
+		"Access-Control-Max-Age":       "600",
+		"Vary":                         "Origin",
+	}
+	for k, v := range want {
+		if got := w.Header().Get(k); got != v {
+			t.Errorf("%s: want %q, got %q", k, v, got)
+		}
+	}
+}
+
+func TestCORS_ActualRequest(t *testing.T) {
+	r := newCORSRouter(t)
+
+	req := httptest.NewRequest(http.MethodPut, "/orders", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK || w.Body.String() != "updated" {
+		t.Fatalf("expected handler response, got %d %q", w.Code, w.Body.String())
+	}
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "https://app.example.com" {
+		t.Errorf("expected allowed origin, got %q", got)
+	}
+	if got := w.Header().Get("Access-Control-Expose-Headers"); got != "X-Request-Id" {
+		t.Errorf("expected exposed headers, got %q", got)
+	}
+}
+
+func TestCORS_DisallowedOrigin(t *testing.T) {
+	r := newCORSRouter(t)
+
+	req := httptest.NewRequest(http.MethodPut, "/orders", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("expected no CORS headers for a disallowed origin, got %q", got)
+	}
+}
+
+func TestCORS_SiblingRouteUnaffected(t *testing.T) {
+	r := newCORSRouter(t)
+
+	req := httptest.NewRequest(http.MethodPut, "/reports", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK || w.Body.String() != "report" {
+		t.Fatalf("expected handler response, got %d %q", w.Code, w.Body.String())
+	}
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("expected no CORS headers on the sibling route, got %q", got)
+	}
+
+	preflight := httptest.NewRequest(http.MethodOptions, "/reports", nil)
+	preflight.Header.Set("Origin", "https://app.example.com")
+	preflight.Header.Set("Access-Control-Request-Method", http.MethodPut)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, preflight)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected the sibling preflight to miss, got %d", w.Code)
+	}
+}
+
+func TestCORS_VaryOrigin(t *testing.T) {
+	tests := []struct {
+		name    string
+		origins []string
+		origin  string
+		want    string
+	}{
+		{"allowed origin", []string{"https://app.example.com"}, "https://app.example.com", "Origin"},
+		{"disallowed origin", []string{"https://app.example.com"}, "https://evil.example.com", "Origin"},
+		{"no origin", []string{"https://app.example.com"}, "", "Origin"},
+		{"wildcard", []string{"*"}, "https://app.example.com", ""},
+		{"wildcard and list", []string{"*", "https://app.example.com"}, "https://app.example.com", "Origin"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := router.CORS(router.CORSConfig{AllowedOrigins: tt.origins})(NewTestHandler(http.StatusOK, "ok"))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			w := httptest.NewRecorder()
+			h(req).Respond(w, req)
+
+			if w.Code != http.StatusOK || w.Body.String() != "ok" {
+				t.Fatalf("expected handler response, got %d %q", w.Code, w.Body.String())
+			}
+			if got := w.Header().Get("Vary"); got != tt.want {
+				t.Errorf("Vary: want %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCORS_WildcardWithCredentialsPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected CORS to panic on \"*\" with AllowCredentials")
+		}
+	}()
+	router.CORS(router.CORSConfig{AllowedOrigins: []string{"*"}, AllowCredentials: true})
+}
@@ -0,0 +1,87 @@
+package router
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/elmq0022/kami/responders"
+	"github.com/elmq0022/kami/types"
+)
+
+const csrfTokenKey contextKey = "csrfTokenKey"
+
+// CSRFConfig configures the CSRF middleware.
+type CSRFConfig struct {
+	// CookieName is the name of the cookie holding the token. Defaults to "kami_csrf".
+	CookieName string
+	// HeaderName is the request header checked for the token. Defaults to "X-CSRF-Token".
+	HeaderName string
+	// FormField is the form field checked when the header is absent. Defaults to "csrf_token".
+	FormField string
+	// Path is the cookie path. Defaults to "/".
+	Path     string
+	Secure   bool
+	SameSite http.SameSite
+}
+
+// CSRF returns a middleware implementing double-submit cookie CSRF protection.
+// Safe methods (GET, HEAD, OPTIONS, TRACE) pass through and have the token cookie issued or refreshed.
+// Unsafe methods must echo the cookie's token in the configured header or form field,
+// otherwise they receive 403 Forbidden. Handlers can embed the token in pages via GetCSRFToken.
+func CSRF(cfg CSRFConfig) types.Middleware {
+	if cfg.CookieName == "" {
+		cfg.CookieName = "kami_csrf"
+	}
+	if cfg.HeaderName == "" {
+		cfg.HeaderName = "X-CSRF-Token"
+	}
+	if cfg.FormField == "" {
+		cfg.FormField = "csrf_token"
+	}
+	if cfg.Path == "" {
+		cfg.Path = "/"
+	}
+
+	return func(next types.Handler) types.Handler {
+		return func(req *http.Request) types.Responder {
+			token := ""
+			if c, err := req.Cookie(cfg.CookieName); err == nil {
+				token = c.Value
+			}
+
+			switch req.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+				if token == "" {
+					token = newSessionID()
+				}
+				ctx := context.WithValue(req.Context(), csrfTokenKey, token)
+				return responders.WithCookie(next(req.WithContext(ctx)), &http.Cookie{
+					Name:     cfg.CookieName,
+					Value:    token,
+					Path:     cfg.Path,
+					Secure:   cfg.Secure,
+					SameSite: cfg.SameSite,
+				})
+			}
+
+			sent := req.Header.Get(cfg.HeaderName)
+			if sent == "" {
+				sent = req.PostFormValue(cfg.FormField)
+			}
+			if token == "" || subtle.ConstantTimeCompare([]byte(sent), []byte(token)) != 1 {
+				return responders.JSONErrorResponse(http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			}
+
+			ctx := context.WithValue(req.Context(), csrfTokenKey, token)
+			return next(req.WithContext(ctx))
+		}
+	}
+}
+
+// GetCSRFToken returns the CSRF token for the request, for embedding in forms or pages.
+// Returns an empty string outside of the CSRF middleware.
+func GetCSRFToken(ctx context.Context) string {
+	t, _ := ctx.Value(csrfTokenKey).(string)
+	return t
+}
@@ -0,0 +1,101 @@
+package router_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/elmq0022/kami/router"
+	"github.com/elmq0022/kami/types"
+)
+
+func newCSRFRouter(t *testing.T) *router.Router {
+	t.Helper()
+	r, err := router.New()
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+
+	g := r.Use(router.CSRF(router.CSRFConfig{})).Prefix("/form")
+	g.GET(func(req *http.Request) types.Responder {
+		return &testResponder{Status: http.StatusOK, Body: router.GetCSRFToken(req.Context())}
+	})
+	g.POST(NewTestHandler(http.StatusOK, "saved"))
+	return r
+}
+
+func issueCSRFToken(t *testing.T, r *router.Router) *http.Cookie {
+	t.Helper()
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/form", nil))
+
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Value == "" {
+		t.Fatalf("expected a CSRF cookie, got %v", cookies)
+	}
+	if w.Body.String() != cookies[0].Value {
+		t.Fatalf("expected GetCSRFToken to match the cookie, got %q", w.Body.String())
+	}
+	return cookies[0]
+}
+
+func TestCSRF_ValidToken(t *testing.T) {
+	r := newCSRFRouter(t)
+	cookie := issueCSRFToken(t, r)
+
+	t.Run("header", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/form", nil)
+		req.AddCookie(cookie)
+		req.Header.Set("X-CSRF-Token", cookie.Value)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+		}
+	})
+
+	t.Run("form field", func(t *testing.T) {
+		form := url.Values{"csrf_token": {cookie.Value}}
+		req := httptest.NewRequest(http.MethodPost, "/form", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.AddCookie(cookie)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+		}
+	})
+}
+
+func TestCSRF_MissingToken(t *testing.T) {
+	r := newCSRFRouter(t)
+	cookie := issueCSRFToken(t, r)
+
+	req := httptest.NewRequest(http.MethodPost, "/form", nil)
+	req.AddCookie(cookie)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected status %d, got %d", http.StatusForbidden, w.Code)
+	}
+}
+
+func TestCSRF_MismatchedToken(t *testing.T) {
+	r := newCSRFRouter(t)
+	cookie := issueCSRFToken(t, r)
+
+	req := httptest.NewRequest(http.MethodPost, "/form", nil)
+	req.AddCookie(cookie)
+	req.Header.Set("X-CSRF-Token", "not-the-token")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected status %d, got %d", http.StatusForbidden, w.Code)
+	}
+}
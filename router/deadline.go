@@ -0,0 +1,97 @@
+package router
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/elmq0022/kami/types"
+)
+
+// DeadlineFromHeader returns a middleware that applies a deadline sent by the caller in header to
+// the request context, so downstream calls made with it give up when the caller stops waiting.
+// The value is a Go duration such as "1.5s", or an RFC 3339 timestamp. For the "grpc-timeout"
+// header the gRPC format is used instead: an integer followed by one of H, M, S, m, u or n.
+// Missing, malformed or non-positive values are ignored and the request proceeds without a deadline.
+// The context is released once the response has been written.
+func DeadlineFromHeader(header string) types.Middleware {
+	grpc := strings.EqualFold(header, "grpc-timeout")
+
+	return func(next types.Handler) types.Handler {
+		return func(req *http.Request) types.Responder {
+			deadline, ok := parseDeadline(req.Header.Get(header), grpc)
+			if !ok {
+				return next(req)
+			}
+
+			ctx, cancel := context.WithDeadline(req.Context(), deadline)
+			return &cancelResponder{inner: next(req.WithContext(ctx)), cancel: cancel}
+		}
+	}
+}
+
+func parseDeadline(value string, grpc bool) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, false
+	}
+
+	var d time.Duration
+	switch {
+	case grpc:
+		var ok bool
+		if d, ok = parseGRPCTimeout(value); !ok {
+			return time.Time{}, false
+		}
+	default:
+		if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+			return t, time.Until(t) > 0
+		}
+		var err error
+		if d, err = time.ParseDuration(value); err != nil {
+			return time.Time{}, false
+		}
+	}
+	if d <= 0 {
+		return time.Time{}, false
+	}
+	return time.Now().Add(d), true
+}
+
+var grpcTimeoutUnits = map[byte]time.Duration{
+	'H': time.Hour,
+	'M': time.Minute,
+	'S': time.Second,
+	'm': time.Millisecond,
+	'u': time.Microsecond,
+	'n': time.Nanosecond,
+}
+
+// parseGRPCTimeout parses a gRPC timeout, at most eight digits followed by a unit.
+func parseGRPCTimeout(value string) (time.Duration, bool) {
+	if len(value) < 2 || len(value) > 9 {
+		return 0, false
+	}
+	unit, ok := grpcTimeoutUnits[value[len(value)-1]]
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(value[:len(value)-1], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return time.Duration(n) * unit, true
+}
+
+// cancelResponder releases a derived request context once the response has been written.
+type cancelResponder struct {
+	inner  types.Responder
+	cancel context.CancelFunc
+}
+
+func (c *cancelResponder) Respond(w http.ResponseWriter, req *http.Request) {
+	defer c.cancel()
+	c.inner.Respond(w, req)
+}
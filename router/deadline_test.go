@@ -0,0 +1,64 @@
+package router_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/elmq0022/kami/router"
+	"github.com/elmq0022/kami/types"
+)
+
+func TestDeadlineFromHeader(t *testing.T) {
+	tests := []struct {
+		name         string
+		header       string
+		value        string
+		wantDeadline bool
+		wantWithin   time.Duration
+	}{
+		{"go duration", "X-Request-Deadline", "2s", true, 2 * time.Second},
+		{"rfc3339 timestamp", "X-Request-Deadline", time.Now().Add(time.Minute).Format(time.RFC3339Nano), true, time.Minute},
+		{"grpc timeout", "grpc-timeout", "500m", true, 500 * time.Millisecond},
+		{"malformed", "X-Request-Deadline", "soon", false, 0},
+		{"negative", "X-Request-Deadline", "-1s", false, 0},
+		{"malformed grpc unit", "grpc-timeout", "5x", false, 0},
+		{"missing", "X-Request-Deadline", "", false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var deadline time.Time
+			var hasDeadline bool
+
+			r, err := router.New()
+			if err != nil {
+				t.Fatalf("failed to create router: %v", err)
+			}
+			r.Use(router.DeadlineFromHeader(tt.header)).Prefix("/work").GET(func(req *http.Request) types.Responder {
+				deadline, hasDeadline = req.Context().Deadline()
+				return &testResponder{Status: http.StatusOK, Body: "done"}
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/work", nil)
+			if tt.value != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status 200, got %d", w.Code)
+			}
+			if hasDeadline != tt.wantDeadline {
+				t.Fatalf("expected deadline %v, got %v", tt.wantDeadline, hasDeadline)
+			}
+			if tt.wantDeadline {
+				if remaining := time.Until(deadline); remaining <= 0 || remaining > tt.wantWithin {
+					t.Errorf("expected deadline within %v, got %v", tt.wantWithin, remaining)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package router
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/elmq0022/kami/responders"
+	"github.com/elmq0022/kami/types"
+)
+
+// DecompressRequest returns a middleware that transparently decompresses request bodies sent
+// with Content-Encoding gzip or deflate, so handlers read plain bytes. The Content-Encoding
+// header is removed and ContentLength becomes unknown. A body whose compression header is
+// malformed receives 400 Bad Request, and any other encoding receives 415 Unsupported Media Type.
+// Corruption later in the stream surfaces as a read error to the handler.
+func DecompressRequest() types.Middleware {
+	return func(next types.Handler) types.Handler {
+		return func(req *http.Request) types.Responder {
+			var body io.ReadCloser
+			var err error
+
+			switch strings.ToLower(strings.TrimSpace(req.Header.Get("Content-Encoding"))) {
+			case "", "identity":
+				return next(req)
+			case "gzip", "x-gzip":
+				body, err = gzip.NewReader(req.Body)
+			case "deflate":
+				body, err = zlib.NewReader(req.Body)
+			default:
+				return responders.JSONErrorResponse(
+					http.StatusText(http.StatusUnsupportedMediaType),
+					http.StatusUnsupportedMediaType,
+				)
+			}
+			if err != nil {
+				return responders.JSONErrorResponse("malformed compressed body", http.StatusBadRequest)
+			}
+
+			r2 := req.Clone(req.Context())
+			r2.Body = &decompressedBody{ReadCloser: body, raw: req.Body}
+			r2.Header.Del("Content-Encoding")
+			r2.Header.Del("Content-Length")
+			r2.ContentLength = -1
+			return next(r2)
+		}
+	}
+}
+
+// decompressedBody closes both the decompressor and the underlying request body.
+type decompressedBody struct {
+	io.ReadCloser
+	raw io.Closer
+}
+
+func (d *decompressedBody) Close() error {
+	err := d.ReadCloser.Close()
+	if rerr := d.raw.Close(); err == nil {
+		err = rerr
+	}
+	return err
+}
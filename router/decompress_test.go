@@ -0,0 +1,96 @@
+package router_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/elmq0022/kami/router"
+	"github.com/elmq0022/kami/types"
+)
+
+func newDecompressRouter(t *testing.T) *router.Router {
+	t.Helper()
+	r, err := router.New()
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+	r.Use(router.DecompressRequest()).Prefix("/upload").POST(func(req *http.Request) types.Responder {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			return &testResponder{Status: http.StatusBadRequest, Body: err.Error()}
+		}
+		return &testResponder{Status: http.StatusOK, Body: req.Header.Get("Content-Encoding") + string(body)}
+	})
+	return r
+}
+
+func TestDecompressRequest(t *testing.T) {
+	r := newDecompressRouter(t)
+
+	var gz bytes.Buffer
+	gw := gzip.NewWriter(&gz)
+	io.WriteString(gw, "hello gzip")
+	gw.Close()
+
+	var zl bytes.Buffer
+	zw := zlib.NewWriter(&zl)
+	io.WriteString(zw, "hello deflate")
+	zw.Close()
+
+	tests := []struct {
+		name       string
+		encoding   string
+		body       []byte
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "gzip", encoding: "gzip", body: gz.Bytes(), wantStatus: http.StatusOK, wantBody: "hello gzip"},
+		{name: "deflate", encoding: "deflate", body: zl.Bytes(), wantStatus: http.StatusOK, wantBody: "hello deflate"},
+		{name: "uncompressed", body: []byte("plain"), wantStatus: http.StatusOK, wantBody: "plain"},
+		{name: "corrupt gzip", encoding: "gzip", body: []byte("not gzip at all"), wantStatus: http.StatusBadRequest},
+		{name: "unsupported encoding", encoding: "br", body: []byte("..."), wantStatus: http.StatusUnsupportedMediaType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/upload", bytes.NewReader(tt.body))
+			if tt.encoding != "" {
+				req.Header.Set("Content-Encoding", tt.encoding)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if tt.wantBody != "" && w.Body.String() != tt.wantBody {
+				t.Fatalf("expected body %q, got %q", tt.wantBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestDecompressRequest_TruncatedStream(t *testing.T) {
+	r := newDecompressRouter(t)
+
+	var gz bytes.Buffer
+	gw := gzip.NewWriter(&gz)
+	io.WriteString(gw, strings.Repeat("data", 1000))
+	gw.Close()
+	truncated := gz.Bytes()[:gz.Len()/2]
+
+	req := httptest.NewRequest(http.MethodPost, "/upload", bytes.NewReader(truncated))
+	req.Header.Set("Content-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected the handler to see a read error, got %d", w.Code)
+	}
+}
@@ -0,0 +1,55 @@
+package router
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/elmq0022/kami/types"
+)
+
+// faviconMaxAge is how long browsers may cache the icon before revalidating.
+const faviconMaxAge = 24 * time.Hour
+
+// Favicon registers a GET /favicon.ico route, relative to the router's prefix, that serves the
+// file name from f with a content type derived from its extension and a one-day Cache-Control.
+// The file is read once at registration, so embed.FS and on-disk icons behave the same.
+// Panics if the file cannot be read.
+func (r *Router) Favicon(f fs.FS, name string) {
+	data, err := fs.ReadFile(f, name)
+	if err != nil {
+		panic(fmt.Sprintf("favicon %s: %v", name, err))
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(name))
+	switch {
+	case strings.EqualFold(path.Ext(name), ".ico"):
+		contentType = "image/x-icon"
+	case contentType == "":
+		contentType = http.DetectContentType(data)
+	}
+	cacheControl := fmt.Sprintf("public, max-age=%d", int(faviconMaxAge.Seconds()))
+	modTime := time.Now()
+
+	r.Prefix("/favicon.ico").GET(func(req *http.Request) types.Responder {
+		return &faviconResponder{data: data, contentType: contentType, cacheControl: cacheControl, modTime: modTime}
+	})
+}
+
+type faviconResponder struct {
+	data         []byte
+	contentType  string
+	cacheControl string
+	modTime      time.Time
+}
+
+func (f *faviconResponder) Respond(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", f.contentType)
+	w.Header().Set("Cache-Control", f.cacheControl)
+	http.ServeContent(w, req, "favicon.ico", f.modTime, bytes.NewReader(f.data))
+}
@@ -0,0 +1,66 @@
+package router_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/elmq0022/kami/router"
+)
+
+func TestFavicon(t *testing.T) {
+	assets := fstest.MapFS{
+		"icons/favicon.ico": {Data: []byte("\x00\x00\x01\x00icon")},
+		"icons/logo.png":    {Data: []byte("\x89PNG\r\n\x1a\npng")},
+	}
+
+	tests := []struct {
+		name            string
+		file            string
+		wantContentType string
+	}{
+		{"ico", "icons/favicon.ico", "image/x-icon"},
+		{"png", "icons/logo.png", "image/png"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := router.New()
+			if err != nil {
+				t.Fatalf("failed to create router: %v", err)
+			}
+			r.Favicon(assets, tt.file)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/favicon.ico", nil))
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status 200, got %d", w.Code)
+			}
+			if got := w.Header().Get("Content-Type"); got != tt.wantContentType {
+				t.Errorf("expected Content-Type %q, got %q", tt.wantContentType, got)
+			}
+			if got := w.Header().Get("Cache-Control"); got != "public, max-age=86400" {
+				t.Errorf("expected Cache-Control, got %q", got)
+			}
+			if got := w.Body.String(); got != string(assets[tt.file].Data) {
+				t.Errorf("expected icon bytes, got %q", got)
+			}
+		})
+	}
+}
+
+func TestFavicon_MissingFilePanics(t *testing.T) {
+	r, err := router.New()
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for a missing favicon")
+		}
+	}()
+	r.Favicon(fstest.MapFS{}, "favicon.ico")
+}
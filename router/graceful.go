@@ -0,0 +1,118 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// listenerFDEnv tells a re-executed child which file descriptor holds the inherited listener.
+const listenerFDEnv = "KAMI_LISTENER_FD"
+
+// gracefulShutdownTimeout bounds how long RunGraceful waits for in-flight requests.
+const gracefulShutdownTimeout = 30 * time.Second
+
+// RunGraceful serves the router on addr and handles signals for zero-downtime deploys.
+// On SIGTERM or interrupt it stops accepting connections and waits up to 30 seconds for
+// in-flight requests to complete. On SIGHUP it re-executes the binary, handing the listening
+// socket to the child, and then shuts down gracefully so the child takes over without dropping
+// connections. A process started by such a handoff serves on the inherited socket instead of
+// listening on addr. Returns nil after a clean shutdown.
+func (r *Router) RunGraceful(addr string) error {
+	r.started.Store(true)
+
+	ln, err := inheritedListener()
+	if err != nil {
+		return err
+	}
+	if ln == nil {
+		if ln, err = net.Listen("tcp", addr); err != nil {
+			return err
+		}
+	}
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGHUP, syscall.SIGTERM, os.Interrupt)
+	defer signal.Stop(sigs)
+
+	srv := &http.Server{Handler: r.Handler()}
+	errc := make(chan error, 1)
+	go func() { errc <- srv.Serve(ln) }()
+	log.Printf("Starting server on %s", ln.Addr())
+
+	for {
+		select {
+		case err := <-errc:
+			return err
+		case sig := <-sigs:
+			if sig == syscall.SIGHUP {
+				if err := handOffListener(ln); err != nil {
+					log.Printf("graceful restart failed, continuing to serve: %v", err)
+					continue
+				}
+			}
+
+			log.Printf("Shutting down server on %s", ln.Addr())
+			ctx, cancel := context.WithTimeout(context.Background(), gracefulShutdownTimeout)
+			defer cancel()
+			if err := srv.Shutdown(ctx); err != nil {
+				return err
+			}
+			if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+				return err
+			}
+			return nil
+		}
+	}
+}
+
+// inheritedListener returns the listener passed by a parent's handoff, or nil if there is none.
+func inheritedListener() (net.Listener, error) {
+	v := os.Getenv(listenerFDEnv)
+	if v == "" {
+		return nil, nil
+	}
+	os.Unsetenv(listenerFDEnv)
+
+	fd, err := strconv.Atoi(v)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s %q: %w", listenerFDEnv, v, err)
+	}
+	f := os.NewFile(uintptr(fd), "listener")
+	defer f.Close()
+	return net.FileListener(f)
+}
+
+// handOffListener starts a copy of the running binary that inherits ln.
+func handOffListener(ln net.Listener) error {
+	tl, ok := ln.(*net.TCPListener)
+	if !ok {
+		return fmt.Errorf("cannot hand off listener of type %T", ln)
+	}
+	f, err := tl.File()
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command(exe, os.Args[1:]...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	// ExtraFiles[0] becomes fd 3 in the child
+	cmd.ExtraFiles = []*os.File{f}
+	cmd.Env = append(os.Environ(), listenerFDEnv+"=3")
+	return cmd.Start()
+}
@@ -0,0 +1,65 @@
+package router
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/elmq0022/kami/responders"
+	"github.com/elmq0022/kami/types"
+)
+
+// HandlerFuncE is a handler that reports failure through an idiomatic error return.
+type HandlerFuncE func(req *http.Request) (types.Responder, error)
+
+// ErrorHandler maps an error returned by a HandlerFuncE to a responder.
+type ErrorHandler func(req *http.Request, err error) types.Responder
+
+const errorHandlerKey contextKey = "errorHandlerKey"
+
+// DefaultErrorHandler is the ErrorHandler used when none is configured.
+// It returns a 500 JSON error without exposing the error text to the client.
+func DefaultErrorHandler(req *http.Request, err error) types.Responder {
+	return responders.JSONErrorResponse(
+		http.StatusText(http.StatusInternalServerError),
+		http.StatusInternalServerError,
+	)
+}
+
+// WithErrorHandler sets the ErrorHandler used by HandlerE for requests served by the router.
+// If not specified, DefaultErrorHandler is used.
+func WithErrorHandler(h ErrorHandler) Option {
+	return func(r *Router) {
+		r.errorHandler = h
+	}
+}
+
+// HandlerE adapts an error-returning handler to a types.Handler.
+// A non-nil error is mapped to a responder by the router's ErrorHandler (see WithErrorHandler),
+// defaulting to a 500 JSON error response.
+// A nil error with a nil responder produces 204 No Content, so handlers that
+// only perform work can simply return (nil, nil).
+func HandlerE(fn HandlerFuncE) types.Handler {
+	return func(req *http.Request) types.Responder {
+		responder, err := fn(req)
+		if err != nil {
+			return getErrorHandler(req.Context())(req, err)
+		}
+
+		if responder == nil {
+			return responders.NoContentResponse()
+		}
+
+		return responder
+	}
+}
+
+func withErrorHandler(ctx context.Context, h ErrorHandler) context.Context {
+	return context.WithValue(ctx, errorHandlerKey, h)
+}
+
+func getErrorHandler(ctx context.Context) ErrorHandler {
+	if h, ok := ctx.Value(errorHandlerKey).(ErrorHandler); ok {
+		return h
+	}
+	return DefaultErrorHandler
+}
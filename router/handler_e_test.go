@@ -0,0 +1,113 @@
+package router_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/elmq0022/kami/router"
+	"github.com/elmq0022/kami/types"
+)
+
+func TestHandlerE(t *testing.T) {
+	tests := []struct {
+		name       string
+		handler    router.HandlerFuncE
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name: "responder returned",
+			handler: func(req *http.Request) (types.Responder, error) {
+				return &testResponder{Status: http.StatusOK, Body: "ok"}, nil
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   "ok",
+		},
+		{
+			name: "nil responder and nil error",
+			handler: func(req *http.Request) (types.Responder, error) {
+				return nil, nil
+			},
+			wantStatus: http.StatusNoContent,
+			wantBody:   "",
+		},
+		{
+			name: "error returned",
+			handler: func(req *http.Request) (types.Responder, error) {
+				return nil, errors.New("boom")
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   `{"msg":"Internal Server Error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := router.New()
+			r.Prefix("/work").POST(router.HandlerE(tt.handler))
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/work", nil)
+			r.ServeHTTP(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("want %d, got %d", tt.wantStatus, rr.Code)
+			}
+			if rr.Body.String() != tt.wantBody {
+				t.Fatalf("want %q, got %q", tt.wantBody, rr.Body.String())
+			}
+		})
+	}
+}
+
+type notFoundError struct{ id string }
+
+func (e notFoundError) Error() string { return "missing " + e.id }
+
+func TestHandlerE_WithErrorHandler(t *testing.T) {
+	var gotErr error
+	errorHandler := func(req *http.Request, err error) types.Responder {
+		gotErr = err
+		var nf notFoundError
+		if errors.As(err, &nf) {
+			return &testResponder{Status: http.StatusNotFound, Body: nf.Error()}
+		}
+		return router.DefaultErrorHandler(req, err)
+	}
+
+	r, _ := router.New(router.WithErrorHandler(errorHandler))
+	r.Prefix("/items/:id").GET(router.HandlerE(func(req *http.Request) (types.Responder, error) {
+		id := router.GetParams(req.Context())["id"]
+		if id == "42" {
+			return &testResponder{Status: http.StatusOK, Body: "found"}, nil
+		}
+		return nil, notFoundError{id: id}
+	}))
+
+	t.Run("success", func(t *testing.T) {
+		gotErr = nil
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/items/42", nil))
+
+		if rr.Code != http.StatusOK || rr.Body.String() != "found" {
+			t.Fatalf("want 200 %q, got %d %q", "found", rr.Code, rr.Body.String())
+		}
+		if gotErr != nil {
+			t.Fatalf("error handler should not run on success, got %v", gotErr)
+		}
+	})
+
+	t.Run("error mapped", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/items/7", nil))
+
+		if rr.Code != http.StatusNotFound || rr.Body.String() != "missing 7" {
+			t.Fatalf("want 404 %q, got %d %q", "missing 7", rr.Code, rr.Body.String())
+		}
+		if !errors.As(gotErr, &notFoundError{}) {
+			t.Fatalf("expected error handler to receive notFoundError, got %v", gotErr)
+		}
+	})
+}
@@ -0,0 +1,99 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/elmq0022/kami/responders"
+	"github.com/elmq0022/kami/types"
+)
+
+// HealthCheck reports whether a dependency is healthy. It should return promptly
+// once ctx is done.
+type HealthCheck func(ctx context.Context) error
+
+// NamedCheck gives check a name under which its failures are reported by a Health endpoint.
+func NamedCheck(name string, check HealthCheck) HealthCheck {
+	return func(ctx context.Context) error {
+		if err := runCheck(ctx, check); err != nil {
+			return &namedCheckError{name: name, err: err}
+		}
+		return nil
+	}
+}
+
+type namedCheckError struct {
+	name string
+	err  error
+}
+
+func (e *namedCheckError) Error() string { return e.err.Error() }
+
+func (e *namedCheckError) Unwrap() error { return e.err }
+
+// runCheck runs check, turning a panic into an error so one misbehaving check cannot crash
+// the process from the goroutine it runs in.
+func runCheck(ctx context.Context, check HealthCheck) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("check panicked: %v", p)
+		}
+	}()
+	return check(ctx)
+}
+
+// HealthStatus is the JSON body served by a Health endpoint.
+type HealthStatus struct {
+	Status string `json:"status"`
+	// Failures maps each failing check to its error. Checks are keyed by the name given with
+	// NamedCheck, or otherwise by their index in registration order.
+	Failures map[string]string `json:"failures,omitempty"`
+}
+
+// Health registers a GET endpoint at path, relative to the router's prefix, that runs the
+// given checks concurrently with the request context. It responds 200 with {"status":"ok"}
+// when every check passes, and 503 with the failing checks' errors otherwise.
+// A check that panics is reported as failed. With no checks it acts as a plain liveness probe.
+//
+//	r.Health("/healthz")
+//	r.Health("/readyz", router.NamedCheck("db", db.PingContext), router.NamedCheck("cache", cache.Ping))
+func (r *Router) Health(path string, checks ...HealthCheck) {
+	r.Prefix(path).GET(func(req *http.Request) types.Responder {
+		errs := make([]error, len(checks))
+
+		var wg sync.WaitGroup
+		for i, check := range checks {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs[i] = runCheck(req.Context(), check)
+			}()
+		}
+		wg.Wait()
+
+		status := HealthStatus{Status: "ok"}
+		for i, err := range errs {
+			if err == nil {
+				continue
+			}
+			if status.Failures == nil {
+				status.Failures = make(map[string]string)
+			}
+			key := strconv.Itoa(i)
+			var named *namedCheckError
+			if errors.As(err, &named) {
+				key = named.name
+			}
+			status.Failures[key] = err.Error()
+		}
+		if len(status.Failures) > 0 {
+			status.Status = "unavailable"
+			return responders.JSONResponse(status, http.StatusServiceUnavailable)
+		}
+		return responders.JSONResponse(status, http.StatusOK)
+	})
+}
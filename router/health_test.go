@@ -0,0 +1,100 @@
+package router_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/elmq0022/kami/router"
+)
+
+func checkHealth(t *testing.T, r *router.Router, req *http.Request) (int, router.HealthStatus) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	var status router.HealthStatus
+	if err := json.Unmarshal(w.Body.Bytes(), &status); err != nil {
+		t.Fatalf("failed to decode health status %q: %v", w.Body.String(), err)
+	}
+	return w.Code, status
+}
+
+func TestHealth_AllPassing(t *testing.T) {
+	r, _ := router.New()
+	ok := func(ctx context.Context) error { return nil }
+	r.Health("/readyz", router.NamedCheck("db", ok), ok)
+
+	code, status := checkHealth(t, r, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	if code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, code)
+	}
+	if status.Status != "ok" || len(status.Failures) != 0 {
+		t.Fatalf("expected ok with no failures, got %+v", status)
+	}
+}
+
+func TestHealth_OneFailing(t *testing.T) {
+	r, _ := router.New()
+	r.Health("/readyz",
+		router.NamedCheck("cache", func(ctx context.Context) error { return nil }),
+		router.NamedCheck("db", func(ctx context.Context) error { return errors.New("database unreachable") }),
+	)
+
+	code, status := checkHealth(t, r, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	if code != http.StatusServiceUnavailable {
+		t.Fatalf("expected status %d, got %d", http.StatusServiceUnavailable, code)
+	}
+	if status.Status != "unavailable" {
+		t.Fatalf("expected status unavailable, got %q", status.Status)
+	}
+	if len(status.Failures) != 1 || status.Failures["db"] != "database unreachable" {
+		t.Fatalf("expected failure for the db check, got %v", status.Failures)
+	}
+}
+
+func TestHealth_ContextCancellation(t *testing.T) {
+	r, _ := router.New()
+	r.Health("/readyz", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req := httptest.NewRequest(http.MethodGet, "/readyz", nil).WithContext(ctx)
+
+	code, status := checkHealth(t, r, req)
+
+	if code != http.StatusServiceUnavailable {
+		t.Fatalf("expected status %d, got %d", http.StatusServiceUnavailable, code)
+	}
+	if status.Failures["0"] != context.Canceled.Error() {
+		t.Fatalf("expected check to observe cancellation, got %v", status.Failures)
+	}
+}
+
+func TestHealth_PanickingCheck(t *testing.T) {
+	r, _ := router.New()
+	r.Health("/readyz",
+		func(ctx context.Context) error { return nil },
+		func(ctx context.Context) error { panic("nil pool") },
+		router.NamedCheck("cache", func(ctx context.Context) error { panic("boom") }),
+	)
+
+	code, status := checkHealth(t, r, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	if code != http.StatusServiceUnavailable {
+		t.Fatalf("expected status %d, got %d", http.StatusServiceUnavailable, code)
+	}
+	want := map[string]string{"1": "check panicked: nil pool", "cache": "check panicked: boom"}
+	if !maps.Equal(status.Failures, want) {
+		t.Fatalf("expected failures %v, got %v", want, status.Failures)
+	}
+}
@@ -0,0 +1,62 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/elmq0022/kami/types"
+)
+
+// FromHTTPHandler adapts a standard http.Handler to a kami Handler. The handler writes the
+// response itself when the returned Responder is asked to respond, and sees the routed
+// request, so GetParams works inside it.
+func FromHTTPHandler(h http.Handler) types.Handler {
+	return func(req *http.Request) types.Responder {
+		return &httpHandlerResponder{h: h}
+	}
+}
+
+// FromHTTPMiddleware adapts standard func(http.Handler) http.Handler middleware to a kami Middleware.
+// Because standard middleware works on the ResponseWriter, the wrapped kami handler runs while
+// the response is being written rather than when the middleware is first called, and it receives
+// whatever request, possibly with an enriched context, the standard middleware passes on.
+func FromHTTPMiddleware(mw func(http.Handler) http.Handler) types.Middleware {
+	return func(next types.Handler) types.Handler {
+		h := mw(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			next(req).Respond(w, req)
+		}))
+		return FromHTTPHandler(h)
+	}
+}
+
+// ToHTTPHandler exposes a single kami Handler as a standard http.HandlerFunc, for example to mount
+// it on an http.ServeMux. An empty params map is placed in the context when none is present,
+// so handlers written for the router can call GetParams unchanged.
+// Unlike the router, it does not recover panics.
+func ToHTTPHandler(h types.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if _, ok := req.Context().Value(paramsKey).(map[string]string); !ok {
+			req = req.WithContext(WithParams(req.Context(), map[string]string{}))
+		}
+		h(req).Respond(w, req)
+	}
+}
+
+// httpHandlerResponder is a Responder that hands the response over to a standard http.Handler.
+type httpHandlerResponder struct {
+	h http.Handler
+}
+
+func (hr *httpHandlerResponder) Respond(w http.ResponseWriter, req *http.Request) {
+	hr.h.ServeHTTP(w, req)
+}
+
+// Adapt adapts an adapter-style handler into a Handler that can be registered on a route.
+// The adapter function, such as a JSON adapter, calls a handler of its own signature H and
+// writes the result, or the error it returns, to the ResponseWriter. The adapter runs when the
+// returned Handler's Responder responds, and receives the routed request, so GetParams works
+// inside both the adapter and the handler.
+func Adapt[H any](adapter func(http.ResponseWriter, *http.Request, H), handler H) types.Handler {
+	return FromHTTPHandler(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		adapter(w, req, handler)
+	}))
+}
@@ -0,0 +1,152 @@
+package router_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/elmq0022/kami/router"
+	"github.com/elmq0022/kami/types"
+)
+
+func TestFromHTTPHandler(t *testing.T) {
+	r, err := router.New()
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+	r.Prefix("/users/:id").GET(router.FromHTTPHandler(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("X-Std", "yes")
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte("user " + router.GetParams(req.Context())["id"]))
+	})))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/42", nil))
+
+	if w.Code != http.StatusAccepted {
+		t.Errorf("expected status 202, got %d", w.Code)
+	}
+	if got := w.Body.String(); got != "user 42" {
+		t.Errorf("expected body %q, got %q", "user 42", got)
+	}
+	if got := w.Header().Get("X-Std"); got != "yes" {
+		t.Errorf("expected X-Std header from the stdlib handler, got %q", got)
+	}
+}
+
+type stdKey struct{}
+
+func TestFromHTTPMiddleware(t *testing.T) {
+	std := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			w.Header().Set("X-Std-Middleware", "before")
+			next.ServeHTTP(w, req.WithContext(context.WithValue(req.Context(), stdKey{}, "from std")))
+		})
+	}
+
+	r, err := router.New()
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+	r.Use(router.FromHTTPMiddleware(std)).Prefix("/items/:id").GET(func(req *http.Request) types.Responder {
+		value, _ := req.Context().Value(stdKey{}).(string)
+		return &testResponder{Status: http.StatusOK, Body: value + " " + router.GetParams(req.Context())["id"]}
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items/7", nil))
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d", w.Code)
+	}
+	if got := w.Body.String(); got != "from std 7" {
+		t.Errorf("expected body %q, got %q", "from std 7", got)
+	}
+	if got := w.Header().Get("X-Std-Middleware"); got != "before" {
+		t.Errorf("expected header set by stdlib middleware, got %q", got)
+	}
+}
+
+func TestToHTTPHandler(t *testing.T) {
+	var params map[string]string
+	mux := http.NewServeMux()
+	mux.Handle("/hello", router.ToHTTPHandler(func(req *http.Request) types.Responder {
+		params = router.GetParams(req.Context())
+		return &testResponder{Status: http.StatusOK, Body: "hello from kami"}
+	}))
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/hello", nil))
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d", w.Code)
+	}
+	if got := w.Body.String(); got != "hello from kami" {
+		t.Errorf("expected body %q, got %q", "hello from kami", got)
+	}
+	if params == nil || len(params) != 0 {
+		t.Errorf("expected an empty params map, got %v", params)
+	}
+}
+
+type userResult struct {
+	ID string `json:"id"`
+}
+
+type resultHandler func(*http.Request) (any, int, error)
+
+// jsonAdapter is an adapter in the style of an error-returning JSON adapter: it calls the
+// handler and writes either its result or its error as JSON.
+func jsonAdapter(w http.ResponseWriter, req *http.Request, h resultHandler) {
+	body, status, err := h(req)
+	if err != nil {
+		body = map[string]string{"error": err.Error()}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func TestAdapt(t *testing.T) {
+	r, err := router.New()
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+	r.Prefix("/users/:id").GET(router.Adapt(jsonAdapter, resultHandler(func(req *http.Request) (any, int, error) {
+		id := router.GetParams(req.Context())["id"]
+		if id == "0" {
+			return nil, http.StatusNotFound, errors.New("user not found")
+		}
+		return userResult{ID: id}, http.StatusOK, nil
+	})))
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{"success", "/users/42", http.StatusOK, `{"id":"42"}` + "\n"},
+		{"error", "/users/0", http.StatusNotFound, `{"error":"user not found"}` + "\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+			if got := w.Header().Get("Content-Type"); got != "application/json" {
+				t.Errorf("expected Content-Type application/json, got %q", got)
+			}
+			if got := w.Body.String(); got != tt.wantBody {
+				t.Errorf("expected body %q, got %q", tt.wantBody, got)
+			}
+		})
+	}
+}
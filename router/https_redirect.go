@@ -0,0 +1,66 @@
+package router
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/elmq0022/kami/types"
+)
+
+// HTTPSRedirectConfig configures the RedirectHTTPS middleware.
+type HTTPSRedirectConfig struct {
+	// TrustForwardedProto treats a request as secure when X-Forwarded-Proto is "https".
+	// Enable it only behind a TLS-terminating proxy that sets the header, since clients can forge it.
+	TrustForwardedProto bool
+
+	// Status is the redirect status, http.StatusMovedPermanently or http.StatusPermanentRedirect.
+	// Defaults to 308, which preserves the method and body of non-GET requests.
+	Status int
+
+	// Host replaces the request's host in the redirect target, for example to point at a
+	// different TLS port ("example.com:8443"). Defaults to the request's host without its port.
+	Host string
+}
+
+// RedirectHTTPS returns a middleware that redirects plain-HTTP requests to the https:// URL with the
+// same host, path and query. Requests served over TLS, or reported as HTTPS by a trusted proxy,
+// pass through untouched. Install it with UseGlobal or WithPreRoute to cover unmatched paths too.
+func RedirectHTTPS(cfg HTTPSRedirectConfig) types.Middleware {
+	if cfg.Status == 0 {
+		cfg.Status = http.StatusPermanentRedirect
+	}
+
+	return func(next types.Handler) types.Handler {
+		return func(req *http.Request) types.Responder {
+			if isHTTPS(req, cfg.TrustForwardedProto) {
+				return next(req)
+			}
+
+			host := cfg.Host
+			if host == "" {
+				host = req.Host
+				if h, _, err := net.SplitHostPort(host); err == nil {
+					host = h
+					// SplitHostPort drops the brackets around IPv6 literals
+					if strings.Contains(host, ":") {
+						host = "[" + host + "]"
+					}
+				}
+			}
+			return &redirectResponder{url: "https://" + host + req.URL.RequestURI(), status: cfg.Status}
+		}
+	}
+}
+
+func isHTTPS(req *http.Request, trustForwardedProto bool) bool {
+	if req.TLS != nil {
+		return true
+	}
+	if !trustForwardedProto {
+		return false
+	}
+	// Proxies chained together may append values; the first is what the client used
+	proto, _, _ := strings.Cut(req.Header.Get("X-Forwarded-Proto"), ",")
+	return strings.EqualFold(strings.TrimSpace(proto), "https")
+}
@@ -0,0 +1,86 @@
+package router_test
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/elmq0022/kami/router"
+)
+
+func newHTTPSRouter(t *testing.T, cfg router.HTTPSRedirectConfig) *router.Router {
+	t.Helper()
+	r, err := router.New(router.WithPreRoute(router.RedirectHTTPS(cfg)))
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+	r.Prefix("/account").GET(NewTestHandler(http.StatusOK, "secure"))
+	r.Prefix("/account").POST(NewTestHandler(http.StatusOK, "secure"))
+	return r
+}
+
+func TestRedirectHTTPS_Insecure(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      router.HTTPSRedirectConfig
+		method   string
+		target   string
+		status   int
+		location string
+	}{
+		{"default 308", router.HTTPSRedirectConfig{}, http.MethodPost, "http://example.com:8080/account?tab=1", http.StatusPermanentRedirect, "https://example.com/account?tab=1"},
+		{"301 with host override", router.HTTPSRedirectConfig{Status: http.StatusMovedPermanently, Host: "example.com:8443"}, http.MethodGet, "http://example.com/account", http.StatusMovedPermanently, "https://example.com:8443/account"},
+		{"IPv6 host", router.HTTPSRedirectConfig{}, http.MethodGet, "http://[::1]:80/account?tab=1", http.StatusPermanentRedirect, "https://[::1]/account?tab=1"},
+		{"IPv6 host without port", router.HTTPSRedirectConfig{}, http.MethodGet, "http://[::1]/account", http.StatusPermanentRedirect, "https://[::1]/account"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			newHTTPSRouter(t, tt.cfg).ServeHTTP(w, httptest.NewRequest(tt.method, tt.target, nil))
+
+			if w.Code != tt.status {
+				t.Fatalf("expected status %d, got %d", tt.status, w.Code)
+			}
+			if got := w.Header().Get("Location"); got != tt.location {
+				t.Errorf("expected Location %q, got %q", tt.location, got)
+			}
+		})
+	}
+}
+
+func TestRedirectHTTPS_Secure(t *testing.T) {
+	t.Run("TLS", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "https://example.com/account", nil)
+		req.TLS = &tls.ConnectionState{}
+		w := httptest.NewRecorder()
+		newHTTPSRouter(t, router.HTTPSRedirectConfig{}).ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK || w.Body.String() != "secure" {
+			t.Errorf("expected pass-through, got %d %q", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("trusted X-Forwarded-Proto", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "http://example.com/account", nil)
+		req.Header.Set("X-Forwarded-Proto", "https")
+		w := httptest.NewRecorder()
+		newHTTPSRouter(t, router.HTTPSRedirectConfig{TrustForwardedProto: true}).ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("expected pass-through, got %d", w.Code)
+		}
+	})
+
+	t.Run("untrusted X-Forwarded-Proto", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "http://example.com/account", nil)
+		req.Header.Set("X-Forwarded-Proto", "https")
+		w := httptest.NewRecorder()
+		newHTTPSRouter(t, router.HTTPSRedirectConfig{}).ServeHTTP(w, req)
+
+		if w.Code != http.StatusPermanentRedirect {
+			t.Errorf("expected the forged header to be ignored, got %d", w.Code)
+		}
+	})
+}
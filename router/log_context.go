@@ -0,0 +1,55 @@
+package router
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+
+	"github.com/elmq0022/kami/types"
+)
+
+// RequestIDHeader is the header LoggerContext reads the request ID from.
+const RequestIDHeader = "X-Request-ID"
+
+const loggerKey contextKey = "loggerKey"
+
+// LoggerContext returns a middleware that stores a request-scoped logger in the context.
+// The logger is derived from base with request_id, method and path attributes, so every
+// line a handler logs through Log can be correlated. The request ID is taken from the
+// X-Request-ID header, or generated when the header is absent.
+func LoggerContext(base *slog.Logger) types.Middleware {
+	return func(next types.Handler) types.Handler {
+		return func(req *http.Request) types.Responder {
+			id := req.Header.Get(RequestIDHeader)
+			if id == "" {
+				id = newRequestID()
+			}
+
+			logger := base.With(
+				slog.String("request_id", id),
+				slog.String("method", req.Method),
+				slog.String("path", req.URL.Path),
+			)
+
+			ctx := context.WithValue(req.Context(), loggerKey, logger)
+			return next(req.WithContext(ctx))
+		}
+	}
+}
+
+// Log returns the request-scoped logger stored by LoggerContext.
+// Returns slog.Default() if no logger is present in the context.
+func Log(ctx context.Context) *slog.Logger {
+	if l, ok := ctx.Value(loggerKey).(*slog.Logger); ok {
+		return l
+	}
+	return slog.Default()
+}
+
+func newRequestID() string {
+	var b [8]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
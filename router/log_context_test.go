@@ -0,0 +1,63 @@
+package router_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/elmq0022/kami/router"
+	"github.com/elmq0022/kami/types"
+)
+
+func TestLoggerContext(t *testing.T) {
+	var buf bytes.Buffer
+	base := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	r, _ := router.New()
+	r.Use(router.LoggerContext(base)).Prefix("/orders/:id").GET(func(req *http.Request) types.Responder {
+		router.Log(req.Context()).Info("loading order")
+		return &testResponder{Status: http.StatusOK}
+	})
+
+	t.Run("request id from header", func(t *testing.T) {
+		buf.Reset()
+		req := httptest.NewRequest(http.MethodGet, "/orders/7", nil)
+		req.Header.Set(router.RequestIDHeader, "req-123")
+		r.ServeHTTP(httptest.NewRecorder(), req)
+
+		var entry map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+			t.Fatalf("failed to decode log entry %q: %v", buf.String(), err)
+		}
+
+		want := map[string]string{"request_id": "req-123", "method": "GET", "path": "/orders/7", "msg": "loading order"}
+		for k, v := range want {
+			if entry[k] != v {
+				t.Errorf("%s: want %q, got %v", k, v, entry[k])
+			}
+		}
+	})
+
+	t.Run("generated request id", func(t *testing.T) {
+		buf.Reset()
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orders/8", nil))
+
+		var entry map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+			t.Fatalf("failed to decode log entry %q: %v", buf.String(), err)
+		}
+		if id, _ := entry["request_id"].(string); id == "" {
+			t.Errorf("expected a generated request_id, got %v", entry["request_id"])
+		}
+	})
+}
+
+func TestLog_DefaultsWithoutMiddleware(t *testing.T) {
+	if router.Log(context.Background()) != slog.Default() {
+		t.Fatal("expected slog.Default() when no logger is in the context")
+	}
+}
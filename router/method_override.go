@@ -0,0 +1,43 @@
+package router
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/elmq0022/kami/types"
+)
+
+// MethodOverrideHeader is the header checked by MethodOverride.
+const MethodOverrideHeader = "X-HTTP-Method-Override"
+
+// MethodOverrideField is the form field checked by MethodOverride.
+const MethodOverrideField = "_method"
+
+// MethodOverride returns a middleware that lets HTML forms reach PUT, PATCH and DELETE handlers.
+// On a POST it reads the X-HTTP-Method-Override header, falling back to the _method form field,
+// and rewrites the request method when the value names one of those methods.
+// Routing happens before route middleware runs, so this must be installed with UseGlobal or WithPreRoute.
+func MethodOverride() types.Middleware {
+	return func(next types.Handler) types.Handler {
+		return func(req *http.Request) types.Responder {
+			if req.Method != http.MethodPost {
+				return next(req)
+			}
+
+			method := req.Header.Get(MethodOverrideHeader)
+			if method == "" {
+				method = req.PostFormValue(MethodOverrideField)
+			}
+
+			switch method = strings.ToUpper(method); method {
+			case http.MethodPut, http.MethodPatch, http.MethodDelete:
+				r2 := new(http.Request)
+				*r2 = *req
+				r2.Method = method
+				return next(r2)
+			}
+
+			return next(req)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package router_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/elmq0022/kami/router"
+)
+
+func TestMethodOverride(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		header   string
+		form     url.Values
+		wantBody string
+	}{
+		{name: "form field", method: http.MethodPost, form: url.Values{"_method": {"DELETE"}}, wantBody: "delete"},
+		{name: "lowercase form field", method: http.MethodPost, form: url.Values{"_method": {"put"}}, wantBody: "put"},
+		{name: "header", method: http.MethodPost, header: "DELETE", wantBody: "delete"},
+		{name: "no override", method: http.MethodPost, wantBody: "post"},
+		{name: "unsupported override", method: http.MethodPost, form: url.Values{"_method": {"CONNECT"}}, wantBody: "post"},
+		{name: "ignored on GET", method: http.MethodGet, header: "DELETE", wantBody: "get"},
+	}
+
+	r, _ := router.New(router.WithPreRoute(router.MethodOverride()))
+	items := r.Prefix("/items")
+	items.GET(NewTestHandler(http.StatusOK, "get"))
+	items.POST(NewTestHandler(http.StatusOK, "post"))
+	items.PUT(NewTestHandler(http.StatusOK, "put"))
+	items.DELETE(NewTestHandler(http.StatusOK, "delete"))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/items", strings.NewReader(tt.form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			if tt.header != "" {
+				req.Header.Set(router.MethodOverrideHeader, tt.header)
+			}
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			if rr.Code != http.StatusOK {
+				t.Fatalf("want %d, got %d", http.StatusOK, rr.Code)
+			}
+			if got := rr.Body.String(); got != tt.wantBody {
+				t.Fatalf("want %q, got %q", tt.wantBody, got)
+			}
+		})
+	}
+}
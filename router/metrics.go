@@ -0,0 +1,58 @@
+package router
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/elmq0022/kami/types"
+)
+
+// MetricsCollector receives one observation per request served through the Metrics middleware.
+// Implementations typically forward to a metrics library such as Prometheus.
+type MetricsCollector interface {
+	ObserveRequest(method, pattern string, status int, dur time.Duration)
+}
+
+type nopCollector struct{}
+
+func (nopCollector) ObserveRequest(string, string, int, time.Duration) {}
+
+// NopCollector is a MetricsCollector that discards every observation.
+var NopCollector MetricsCollector = nopCollector{}
+
+// Metrics returns a middleware that reports the method, matched route pattern, final status
+// and duration of each request to collector. The route pattern (e.g. "/users/:id") is used
+// instead of the concrete path to keep label cardinality low. A nil collector uses NopCollector.
+func Metrics(collector MetricsCollector) types.Middleware {
+	if collector == nil {
+		collector = NopCollector
+	}
+
+	return func(next types.Handler) types.Handler {
+		return func(req *http.Request) types.Responder {
+			start := time.Now()
+			responder := next(req)
+			return &metricsResponder{
+				inner:     responder,
+				collector: collector,
+				method:    req.Method,
+				pattern:   GetRoutePattern(req.Context()),
+				start:     start,
+			}
+		}
+	}
+}
+
+type metricsResponder struct {
+	inner     types.Responder
+	collector MetricsCollector
+	method    string
+	pattern   string
+	start     time.Time
+}
+
+func (m *metricsResponder) Respond(w http.ResponseWriter, req *http.Request) {
+	lw := &loggingWriter{ResponseWriter: w, statusCode: http.StatusOK}
+	m.inner.Respond(lw, req)
+	m.collector.ObserveRequest(m.method, m.pattern, lw.statusCode, time.Since(m.start))
+}
@@ -0,0 +1,74 @@
+package router_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/elmq0022/kami/router"
+	"github.com/elmq0022/kami/types"
+)
+
+type observation struct {
+	method  string
+	pattern string
+	status  int
+	dur     time.Duration
+}
+
+type fakeCollector struct {
+	observations []observation
+}
+
+func (f *fakeCollector) ObserveRequest(method, pattern string, status int, dur time.Duration) {
+	f.observations = append(f.observations, observation{method, pattern, status, dur})
+}
+
+func TestMetrics(t *testing.T) {
+	collector := &fakeCollector{}
+
+	r, err := router.New()
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+	r.Use(router.Metrics(collector)).Prefix("/users/:id").DELETE(func(req *http.Request) types.Responder {
+		time.Sleep(time.Millisecond)
+		return &testResponder{Status: http.StatusAccepted, Body: "deleted"}
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/users/42", nil))
+
+	if len(collector.observations) != 1 {
+		t.Fatalf("expected 1 observation, got %d", len(collector.observations))
+	}
+	got := collector.observations[0]
+	if got.method != http.MethodDelete {
+		t.Errorf("expected method %s, got %s", http.MethodDelete, got.method)
+	}
+	if got.pattern != "/users/:id" {
+		t.Errorf("expected pattern %q, got %q", "/users/:id", got.pattern)
+	}
+	if got.status != http.StatusAccepted {
+		t.Errorf("expected status %d, got %d", http.StatusAccepted, got.status)
+	}
+	if got.dur < time.Millisecond {
+		t.Errorf("expected duration of at least 1ms, got %v", got.dur)
+	}
+}
+
+func TestMetrics_NilCollector(t *testing.T) {
+	r, err := router.New()
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+	r.Use(router.Metrics(nil)).Prefix("/").GET(NewTestHandler(http.StatusOK, "ok"))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+}
@@ -0,0 +1,93 @@
+package router
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/elmq0022/kami/types"
+)
+
+type scopedHandler struct {
+	segments []string
+	handler  types.Handler
+}
+
+// NotFound sets the handler used when a lookup misses under the router's current prefix,
+// so that, for example, /api can answer with JSON while the rest of the site serves HTML:
+//
+//	r.Prefix("/api").NotFound(jsonNotFound)
+//
+// The most specific matching prefix wins, and paths outside every scoped prefix fall back to
+// the router-wide handler set with WithNotFound. Prefix params and wildcards match any segment.
+// The handler is wrapped by the router's middleware, like a route registered on it.
+// Panics if called after the router has started serving requests or has been frozen.
+func (r *Router) NotFound(handler types.Handler) {
+	if r.started.Load() {
+		panic(fmt.Sprintf("cannot set not-found handler for %s since the router is running", r.prefix))
+	}
+	if r.frozen.Load() {
+		panic(fmt.Sprintf("cannot set not-found handler for %s since the router is frozen", r.prefix))
+	}
+
+	h := handler
+	for i := len(r.middleware) - 1; i >= 0; i-- {
+		h = r.middleware[i](h)
+	}
+
+	segments := splitPath(r.prefix)
+	scoped := *r.scopedNotFound
+	for i, s := range scoped {
+		if slices.Equal(s.segments, segments) {
+			scoped[i].handler = h
+			return
+		}
+	}
+	*r.scopedNotFound = append(scoped, scopedHandler{segments: segments, handler: h})
+}
+
+// notFoundFor returns the not-found handler with the longest prefix matching path.
+func (r *Router) notFoundFor(path string) types.Handler {
+	h, best := r.notFound, -1
+	segments := splitPath(path)
+	for _, s := range *r.scopedNotFound {
+		if len(s.segments) > best && prefixMatches(s.segments, segments) {
+			h, best = s.handler, len(s.segments)
+		}
+	}
+	return h
+}
+
+func prefixMatches(prefix, segments []string) bool {
+	for i, p := range prefix {
+		if p[0] == '*' {
+			return true
+		}
+		if i >= len(segments) {
+			return false
+		}
+		if p[0] != ':' && p != segments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// withAllowHeader wraps h so its response carries an Allow header listing methods.
+func withAllowHeader(h types.Handler, methods []string) types.Handler {
+	allow := strings.Join(methods, ", ")
+	return func(req *http.Request) types.Responder {
+		return &allowResponder{inner: h(req), allow: allow}
+	}
+}
+
+type allowResponder struct {
+	inner types.Responder
+	allow string
+}
+
+func (a *allowResponder) Respond(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Allow", a.allow)
+	a.inner.Respond(w, req)
+}
@@ -0,0 +1,56 @@
+package router_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/elmq0022/kami/responders"
+	"github.com/elmq0022/kami/router"
+	"github.com/elmq0022/kami/types"
+)
+
+func TestNotFound_ScopedByPrefix(t *testing.T) {
+	r, err := router.New()
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+
+	api := r.Prefix("/api")
+	api.Prefix("/users").GET(NewTestHandler(http.StatusOK, "users"))
+	api.NotFound(func(req *http.Request) types.Responder {
+		return responders.JSONErrorResponse("no such endpoint", http.StatusNotFound)
+	})
+	r.Prefix("/api/v2").NotFound(NewTestHandler(http.StatusNotFound, "v2 missing"))
+	r.Prefix("/tenants/:id").NotFound(NewTestHandler(http.StatusNotFound, "tenant missing"))
+
+	tests := []struct {
+		name            string
+		path            string
+		wantContentType string
+		wantBody        string
+	}{
+		{name: "api prefix", path: "/api/missing", wantContentType: "application/problem+json", wantBody: `{"msg":"no such endpoint"}`},
+		{name: "most specific prefix", path: "/api/v2/missing", wantBody: "v2 missing"},
+		{name: "param prefix", path: "/tenants/7/missing", wantBody: "tenant missing"},
+		{name: "outside scoped prefixes", path: "/missing", wantBody: "Not Found"},
+		{name: "similar but different segment", path: "/apix", wantBody: "Not Found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if w.Code != http.StatusNotFound {
+				t.Fatalf("expected status %d, got %d", http.StatusNotFound, w.Code)
+			}
+			if tt.wantContentType != "" && w.Header().Get("Content-Type") != tt.wantContentType {
+				t.Errorf("expected Content-Type %q, got %q", tt.wantContentType, w.Header().Get("Content-Type"))
+			}
+			if w.Body.String() != tt.wantBody {
+				t.Errorf("expected body %q, got %q", tt.wantBody, w.Body.String())
+			}
+		})
+	}
+}
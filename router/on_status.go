@@ -0,0 +1,34 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/elmq0022/kami/types"
+)
+
+// OnStatus returns a middleware that calls fn with the request and the final response status
+// whenever predicate reports true for that status, for example to count or alert on 5xx
+// responses. The response itself is not altered, and fn runs after it has been written.
+//
+//	router.OnStatus(func(code int) bool { return code >= 500 }, alert)
+func OnStatus(predicate func(int) bool, fn func(*http.Request, int)) types.Middleware {
+	return func(next types.Handler) types.Handler {
+		return func(req *http.Request) types.Responder {
+			return &onStatusResponder{inner: next(req), predicate: predicate, fn: fn}
+		}
+	}
+}
+
+type onStatusResponder struct {
+	inner     types.Responder
+	predicate func(int) bool
+	fn        func(*http.Request, int)
+}
+
+func (o *onStatusResponder) Respond(w http.ResponseWriter, req *http.Request) {
+	lw := &loggingWriter{ResponseWriter: w, statusCode: http.StatusOK}
+	o.inner.Respond(lw, req)
+	if o.predicate(lw.statusCode) {
+		o.fn(req, lw.statusCode)
+	}
+}
@@ -0,0 +1,34 @@
+package router_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/elmq0022/kami/router"
+)
+
+func TestOnStatus(t *testing.T) {
+	var fired []int
+	var paths []string
+	isServerError := func(code int) bool { return code >= 500 }
+
+	r, err := router.New()
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+	alerted := r.Use(router.OnStatus(isServerError, func(req *http.Request, code int) {
+		fired = append(fired, code)
+		paths = append(paths, req.URL.Path)
+	}))
+	alerted.Prefix("/broken").GET(NewTestHandler(http.StatusInternalServerError, "boom"))
+	alerted.Prefix("/fine").GET(NewTestHandler(http.StatusOK, "ok"))
+
+	for _, path := range []string{"/broken", "/fine"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	if len(fired) != 1 || fired[0] != http.StatusInternalServerError || paths[0] != "/broken" {
+		t.Errorf("expected one callback for /broken with 500, got %v %v", paths, fired)
+	}
+}
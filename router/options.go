@@ -16,6 +16,91 @@ type Option func(r *Router)
 func WithNotFound(h types.Handler) Option {
 	return func(r *Router) {
 		r.notFound = h
+		r.customNotFound = true
+	}
+}
+
+// WithMethodNotAllowed sets the handler for requests whose path matches a route registered
+// only for other methods, typically answering 405 Method Not Allowed. The response carries an
+// Allow header listing the registered methods, also available through GetAllowedMethods.
+// Without it, method misses are handled by the not-found handler, as path misses are.
+func WithMethodNotAllowed(h types.Handler) Option {
+	return func(r *Router) {
+		r.notAllowed = h
+	}
+}
+
+// WithGlobalOptions sets a handler for OPTIONS requests that match no registered OPTIONS route,
+// typically to answer CORS preflight requests uniformly. It takes precedence over the
+// not-found handler for those requests, and GetAllowedMethods reports the methods
+// registered for the requested path.
+func WithGlobalOptions(h types.Handler) Option {
+	return func(r *Router) {
+		r.globalOptions = h
+	}
+}
+
+// WithPreRoute is the option form of UseGlobal: New(WithPreRoute(mws...)) is equivalent to
+// calling r.UseGlobal(mws...) on the new router. Pre-route middleware wraps the router's dispatch
+// rather than an individual route and runs before the route lookup, so it can change the request
+// (method, path, headers) in ways that affect which handler is matched.
+func WithPreRoute(mws ...types.Middleware) Option {
+	return func(r *Router) {
+		r.UseGlobal(mws...)
+	}
+}
+
+// WithMaxPathLength rejects requests whose URL path is longer than n bytes with 414 URI Too Long,
+// before pre-route middleware and route lookup, so adversarial paths cost no traversal work.
+// Zero or a negative n disables the check, which is the default.
+func WithMaxPathLength(n int) Option {
+	return func(r *Router) {
+		r.maxPathLength = n
+	}
+}
+
+// WithSkipCanceled discards the response of a request whose context is already canceled when
+// the handler returns, typically because the client disconnected, to avoid a flood of write
+// errors. The responder still runs against a writer that drops its output, so middleware such
+// as PerClientLimit, Logger and Metrics release and observe the request as usual.
+// Skips are logged at most once per ten seconds.
+func WithSkipCanceled() Option {
+	return func(r *Router) {
+		r.skipCanceled = true
+	}
+}
+
+// PanicHandler writes the response for a request whose handling panicked.
+// err is the value recovered from the panic.
+type PanicHandler func(w http.ResponseWriter, req *http.Request, err any)
+
+// WithPanicHandler replaces the router's default panic response, which logs the panic and
+// writes a plain-text 500. The handler runs in ServeHTTP after the panic has been recovered,
+// so it controls the status, headers, body and logging of the response.
+func WithPanicHandler(h PanicHandler) Option {
+	return func(r *Router) {
+		r.panicHandler = h
+	}
+}
+
+// WithDefaultHeaders sets headers written on every response served through Handler (and Run).
+// Handlers may override them since they are set before the request is dispatched.
+func WithDefaultHeaders(h http.Header) Option {
+	return func(r *Router) {
+		if r.defaultHeader == nil {
+			r.defaultHeader = make(http.Header)
+		}
+		for k, v := range h {
+			r.defaultHeader[http.CanonicalHeaderKey(k)] = append([]string(nil), v...)
+		}
+	}
+}
+
+// WithHandlerWrapper wraps the router at the http.Handler level when served through Handler (and Run),
+// for integrating standard library middleware. Wrappers added first are outermost.
+func WithHandlerWrapper(wrappers ...func(http.Handler) http.Handler) Option {
+	return func(r *Router) {
+		r.wrappers = append(r.wrappers, wrappers...)
 	}
 }
 
@@ -56,11 +141,23 @@ func (l *loggingResponder) Respond(w http.ResponseWriter, req *http.Request) {
 	log.Printf("%s %s - %d (%v)", l.method, l.path, lw.statusCode, duration)
 }
 
+// loggingWriter records the status written through it. It implements Flush and Unwrap so
+// streaming responses and connection upgrades keep working behind the middleware that use it.
 type loggingWriter struct {
 	http.ResponseWriter
 	statusCode int
 }
 
+// Flush sends buffered data to the client, if the underlying writer supports it.
+func (lw *loggingWriter) Flush() {
+	http.NewResponseController(lw.ResponseWriter).Flush()
+}
+
+// Unwrap returns the underlying writer, for use by http.ResponseController.
+func (lw *loggingWriter) Unwrap() http.ResponseWriter {
+	return lw.ResponseWriter
+}
+
 func (lw *loggingWriter) WriteHeader(code int) {
 	lw.statusCode = code
 	lw.ResponseWriter.WriteHeader(code)
@@ -1,10 +1,20 @@
 package router_test
 
 import (
+	"bufio"
+	"context"
+	"io"
+	"log"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/elmq0022/kami/responders"
 	"github.com/elmq0022/kami/router"
 	"github.com/elmq0022/kami/types"
 )
@@ -136,7 +146,7 @@ func TestRouteSpecificMiddleware(t *testing.T) {
 		}
 
 		// Global (mw1) -> Route-specific (mw2, mw3) -> handler
-		// Results in: 1 + 2 + 3 (reverse order due to wrapping)
+		// Each test middleware appends after next returns, so the innermost (mw3) appends first
 		want := "321"
 		got := rr.Body.String()
 		if got != want {
@@ -161,3 +171,413 @@ func TestRouteSpecificMiddleware(t *testing.T) {
 		}
 	})
 }
+
+func TestWithNotFound_AllowedMethods(t *testing.T) {
+	var got []string
+	testNotFound := func(r *http.Request) types.Responder {
+		got = router.GetAllowedMethods(r.Context())
+		return &testResponder{Status: http.StatusNotFound, Body: "test not found"}
+	}
+
+	r, _ := router.New(router.WithNotFound(testNotFound))
+	r.Prefix("/items").GET(testHandler)
+
+	t.Run("method mismatch", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/items", nil)
+		r.ServeHTTP(rr, req)
+
+		if want := []string{http.MethodGet}; !slices.Equal(got, want) {
+			t.Fatalf("want %v, got %v", want, got)
+		}
+	})
+
+	t.Run("unknown path", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/missing", nil)
+		r.ServeHTTP(rr, req)
+
+		if len(got) != 0 {
+			t.Fatalf("expected no allowed methods, got %v", got)
+		}
+	})
+}
+
+func TestWithPreRoute(t *testing.T) {
+	rewrite := func(next types.Handler) types.Handler {
+		return func(req *http.Request) types.Responder {
+			req.URL.Path = "/rewritten"
+			return next(req)
+		}
+	}
+
+	r, _ := router.New(router.WithPreRoute(rewrite))
+	r.Prefix("/rewritten").GET(func(req *http.Request) types.Responder {
+		return &testResponder{Status: http.StatusOK, Body: req.URL.Path}
+	})
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/original", nil)
+	r.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("want %d got %d", http.StatusOK, rr.Code)
+	}
+
+	if rr.Body.String() != "/rewritten" {
+		t.Fatalf("want %s, got %s", "/rewritten", rr.Body.String())
+	}
+}
+
+func TestWithGlobalOptions(t *testing.T) {
+	preflight := func(req *http.Request) types.Responder {
+		allowed := strings.Join(router.GetAllowedMethods(req.Context()), ", ")
+		return &testResponder{Status: http.StatusNoContent, Body: allowed}
+	}
+
+	r, _ := router.New(router.WithGlobalOptions(preflight))
+	r.Prefix("/items").GET(testHandler)
+	r.Prefix("/items").POST(testHandler)
+	r.Prefix("/custom").OPTIONS(NewTestHandler(http.StatusOK, "custom options"))
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "registered path", method: http.MethodOptions, path: "/items", wantStatus: http.StatusNoContent, wantBody: "GET, POST"},
+		{name: "unregistered path", method: http.MethodOptions, path: "/anything", wantStatus: http.StatusNoContent, wantBody: ""},
+		{name: "explicit OPTIONS route wins", method: http.MethodOptions, path: "/custom", wantStatus: http.StatusOK, wantBody: "custom options"},
+		{name: "other methods still 404", method: http.MethodGet, path: "/anything", wantStatus: http.StatusNotFound, wantBody: "Not Found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			r.ServeHTTP(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("want %d got %d", tt.wantStatus, rr.Code)
+			}
+			if rr.Body.String() != tt.wantBody {
+				t.Fatalf("want %q, got %q", tt.wantBody, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandle_RouteSpecificMiddleware(t *testing.T) {
+	r, _ := router.New()
+	api := r.Prefix("/api").Use(testMiddleware1)
+
+	// Route-specific middleware runs inside the router's middleware
+	api.Handle(http.MethodGet, "/with-mw", testHandler, testMiddleware2, testMiddleware3)
+	api.Handle(http.MethodGet, "/without-mw", testHandler)
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		// mw1 -> mw2 -> mw3 -> handler; each appends after calling next
+		{"/api/with-mw", "321"},
+		{"/api/without-mw", "1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			r.ServeHTTP(rr, req)
+
+			if rr.Code != http.StatusOK {
+				t.Fatalf("want %d got %d", http.StatusOK, rr.Code)
+			}
+			if got := rr.Body.String(); got != tt.want {
+				t.Fatalf("want %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestVerbMethods_RouteSpecificMiddleware(t *testing.T) {
+	r, _ := router.New()
+
+	// Prefix-style and path-style registration side by side
+	r.Prefix("/prefix-style").GET(testHandler, testMiddleware1, testMiddleware2, testMiddleware3)
+	r.Handle(http.MethodGet, "/path-style", testHandler, testMiddleware1, testMiddleware2, testMiddleware3)
+	r.Prefix("/plain").GET(testHandler)
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/prefix-style", "321"},
+		{"/path-style", "321"},
+		{"/plain", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			r.ServeHTTP(rr, req)
+
+			if rr.Code != http.StatusOK {
+				t.Fatalf("want %d got %d", http.StatusOK, rr.Code)
+			}
+			if got := rr.Body.String(); got != tt.want {
+				t.Fatalf("want %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestHandle_ExecutionOrder(t *testing.T) {
+	var order []string
+	record := func(name string) types.Middleware {
+		return func(next types.Handler) types.Handler {
+			return func(req *http.Request) types.Responder {
+				order = append(order, name)
+				return next(req)
+			}
+		}
+	}
+
+	r, _ := router.New()
+	r.Use(record("router1"), record("router2")).Handle(http.MethodPost, "/items", func(req *http.Request) types.Responder {
+		order = append(order, "handler")
+		return &testResponder{Status: http.StatusCreated}
+	}, record("route1"), record("route2"))
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/items", nil))
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("want %d got %d", http.StatusCreated, rr.Code)
+	}
+	want := []string{"router1", "router2", "route1", "route2", "handler"}
+	if !slices.Equal(order, want) {
+		t.Fatalf("want %v, got %v", want, order)
+	}
+}
+
+func TestUse_ExecutionOrder(t *testing.T) {
+	var order []string
+	record := func(name string) types.Middleware {
+		return func(next types.Handler) types.Handler {
+			return func(req *http.Request) types.Responder {
+				order = append(order, name+" before")
+				resp := next(req)
+				order = append(order, name+" after")
+				return resp
+			}
+		}
+	}
+
+	r, _ := router.New()
+	r.Use(record("a"), record("b")).Use(record("c")).Prefix("/order").GET(func(req *http.Request) types.Responder {
+		order = append(order, "handler")
+		return &testResponder{Status: http.StatusOK}
+	}, record("route"))
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/order", nil))
+
+	want := []string{
+		"a before", "b before", "c before", "route before",
+		"handler",
+		"route after", "c after", "b after", "a after",
+	}
+	if !slices.Equal(order, want) {
+		t.Fatalf("want %v, got %v", want, order)
+	}
+}
+
+func TestWithPanicHandler(t *testing.T) {
+	var recovered any
+	r, _ := router.New(router.WithPanicHandler(func(w http.ResponseWriter, req *http.Request, err any) {
+		recovered = err
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"error":"try again"}`))
+	}))
+	r.Prefix("/panic").GET(func(req *http.Request) types.Responder {
+		panic("boom")
+	})
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+	if recovered != "boom" {
+		t.Fatalf("expected recovered value %q, got %v", "boom", recovered)
+	}
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Fatalf("want %d got %d", http.StatusServiceUnavailable, rr.Code)
+	}
+	if rr.Body.String() != `{"error":"try again"}` {
+		t.Fatalf("unexpected body %q", rr.Body.String())
+	}
+}
+
+func TestWithMaxPathLength(t *testing.T) {
+	var hits int
+	r, err := router.New(
+		router.WithMaxPathLength(16),
+		router.WithPreRoute(func(next types.Handler) types.Handler {
+			return func(req *http.Request) types.Responder {
+				hits++
+				return next(req)
+			}
+		}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+	r.Prefix("/files/*path").GET(NewTestHandler(http.StatusOK, "ok"))
+
+	t.Run("under the limit", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/files/a/b", nil))
+		if w.Code != http.StatusOK {
+			t.Errorf("expected status 200, got %d", w.Code)
+		}
+	})
+
+	t.Run("over the limit", func(t *testing.T) {
+		hits = 0
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/files/"+strings.Repeat("a/", 20), nil))
+		if w.Code != http.StatusRequestURITooLong {
+			t.Errorf("expected status 414, got %d", w.Code)
+		}
+		if hits != 0 {
+			t.Errorf("expected pre-route middleware to be skipped, ran %d times", hits)
+		}
+	})
+}
+
+func TestWithMethodNotAllowed(t *testing.T) {
+	r, err := router.New(
+		router.WithNotFound(func(req *http.Request) types.Responder {
+			return &testResponder{Status: http.StatusNotFound, Body: "no such path"}
+		}),
+		router.WithMethodNotAllowed(func(req *http.Request) types.Responder {
+			allowed := strings.Join(router.GetAllowedMethods(req.Context()), ",")
+			return &testResponder{Status: http.StatusMethodNotAllowed, Body: req.Method + " not in " + allowed}
+		}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+	r.Prefix("/items").GET(testHandler)
+	r.Prefix("/items").PUT(testHandler)
+
+	tests := []struct {
+		name      string
+		method    string
+		path      string
+		wantCode  int
+		wantBody  string
+		wantAllow string
+	}{
+		{"path miss", http.MethodGet, "/missing", http.StatusNotFound, "no such path", ""},
+		{"method miss", http.MethodDelete, "/items", http.StatusMethodNotAllowed, "DELETE not in GET,PUT", "GET, PUT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if w.Code != tt.wantCode {
+				t.Errorf("expected status %d, got %d", tt.wantCode, w.Code)
+			}
+			if got := w.Body.String(); got != tt.wantBody {
+				t.Errorf("expected body %q, got %q", tt.wantBody, got)
+			}
+			if got := w.Header().Get("Allow"); got != tt.wantAllow {
+				t.Errorf("expected Allow %q, got %q", tt.wantAllow, got)
+			}
+		})
+	}
+}
+
+type nopCollector struct{}
+
+func (nopCollector) ObserveRequest(method, pattern string, status int, dur time.Duration) {}
+
+// statusWrappingMiddleware lists the middleware that wrap the ResponseWriter to observe the status.
+var statusWrappingMiddleware = map[string]types.Middleware{
+	"Logger":          router.Logger,
+	"Metrics":         router.Metrics(nopCollector{}),
+	"OnStatus":        router.OnStatus(func(int) bool { return true }, func(*http.Request, int) {}),
+	"SlowRequestWarn": router.SlowRequestWarn(time.Hour),
+	"Cache":           router.Cache(time.Minute, router.CacheConfig{}),
+}
+
+func TestStatusWrappingMiddleware_Flush(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	for name, mw := range statusWrappingMiddleware {
+		t.Run(name, func(t *testing.T) {
+			pr, pw := io.Pipe()
+
+			r, _ := router.New()
+			r.Use(mw).Prefix("/events").GET(func(req *http.Request) types.Responder {
+				return responders.StreamResponse(pr, "text/plain", http.StatusOK)
+			})
+			srv := httptest.NewServer(r)
+			defer srv.Close()
+			defer pw.Close() // ends the stream before the server shuts down
+
+			go pw.Write([]byte("first\n"))
+
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/events", nil)
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("expected the first chunk to be flushed while the stream is open: %v", err)
+			}
+			defer resp.Body.Close()
+
+			line, err := bufio.NewReader(resp.Body).ReadString('\n')
+			if err != nil || line != "first\n" {
+				t.Fatalf("expected %q before the stream ends, got %q (%v)", "first\n", line, err)
+			}
+		})
+	}
+}
+
+func TestStatusWrappingMiddleware_Hijack(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	for name, mw := range statusWrappingMiddleware {
+		t.Run(name, func(t *testing.T) {
+			r, _ := router.New()
+			r.Use(mw).Prefix("/ws").GET(func(req *http.Request) types.Responder {
+				return responders.WebSocketResponse(func(conn net.Conn, rw *bufio.ReadWriter) {})
+			})
+			srv := httptest.NewServer(r)
+			defer srv.Close()
+
+			conn, err := net.Dial("tcp", srv.Listener.Addr().String())
+			if err != nil {
+				t.Fatalf("failed to dial: %v", err)
+			}
+			defer conn.Close()
+			conn.SetDeadline(time.Now().Add(2 * time.Second))
+
+			io.WriteString(conn, "GET /ws HTTP/1.1\r\nHost: test\r\nConnection: Upgrade\r\nUpgrade: websocket\r\n"+
+				"Sec-WebSocket-Version: 13\r\nSec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n\r\n")
+			status, err := bufio.NewReader(conn).ReadString('\n')
+			if err != nil || !strings.Contains(status, "101") {
+				t.Fatalf("expected 101 Switching Protocols, got %q (%v)", status, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package router
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/elmq0022/kami/responders"
+	"github.com/elmq0022/kami/types"
+)
+
+// PerClientLimit returns a middleware that bounds the number of in-flight requests per client IP.
+// A request arriving while its client already has max requests in progress receives
+// 429 Too Many Requests. A slot is held until the responder has finished writing, so slow
+// responses count against the limit. Combine with RealIP when running behind proxies.
+func PerClientLimit(max int) types.Middleware {
+	var mu sync.Mutex
+	inFlight := make(map[string]int)
+
+	release := func(client string) {
+		mu.Lock()
+		defer mu.Unlock()
+		if inFlight[client]--; inFlight[client] <= 0 {
+			delete(inFlight, client)
+		}
+	}
+
+	return func(next types.Handler) types.Handler {
+		return func(req *http.Request) types.Responder {
+			client := remoteHost(req)
+
+			mu.Lock()
+			if inFlight[client] >= max {
+				mu.Unlock()
+				return responders.JSONErrorResponse(http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+			}
+			inFlight[client]++
+			mu.Unlock()
+
+			var once sync.Once
+			done := func() { once.Do(func() { release(client) }) }
+
+			// Release the slot if the handler panics before a responder is returned
+			returned := false
+			defer func() {
+				if !returned {
+					done()
+				}
+			}()
+
+			responder := next(req)
+			returned = true
+			return &releasingResponder{inner: responder, release: done}
+		}
+	}
+}
+
+type releasingResponder struct {
+	inner   types.Responder
+	release func()
+}
+
+func (rr *releasingResponder) Respond(w http.ResponseWriter, req *http.Request) {
+	defer rr.release()
+	rr.inner.Respond(w, req)
+}
@@ -0,0 +1,71 @@
+package router_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/elmq0022/kami/router"
+	"github.com/elmq0022/kami/types"
+)
+
+func TestPerClientLimit(t *testing.T) {
+	entered := make(chan struct{})
+	unblock := make(chan struct{})
+
+	r, _ := router.New()
+	limited := r.Use(router.PerClientLimit(1))
+	limited.Prefix("/slow").GET(func(req *http.Request) types.Responder {
+		entered <- struct{}{}
+		<-unblock
+		return &testResponder{Status: http.StatusOK, Body: "slow"}
+	})
+	limited.Prefix("/fast").GET(NewTestHandler(http.StatusOK, "fast"))
+
+	send := func(path, remoteAddr string) int {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = remoteAddr
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	first := make(chan int)
+	go func() { first <- send("/slow", "192.0.2.1:1000") }()
+	<-entered
+
+	if got := send("/fast", "192.0.2.1:1001"); got != http.StatusTooManyRequests {
+		t.Fatalf("same client over limit: want %d, got %d", http.StatusTooManyRequests, got)
+	}
+	if got := send("/fast", "192.0.2.2:1000"); got != http.StatusOK {
+		t.Fatalf("other client: want %d, got %d", http.StatusOK, got)
+	}
+
+	close(unblock)
+	if got := <-first; got != http.StatusOK {
+		t.Fatalf("first request: want %d, got %d", http.StatusOK, got)
+	}
+
+	if got := send("/fast", "192.0.2.1:1002"); got != http.StatusOK {
+		t.Fatalf("slot should be released after completion: want %d, got %d", http.StatusOK, got)
+	}
+}
+
+func TestPerClientLimit_ReleasesOnPanic(t *testing.T) {
+	r, _ := router.New()
+	limited := r.Use(router.PerClientLimit(1))
+	limited.Prefix("/panic").GET(func(req *http.Request) types.Responder {
+		panic("boom")
+	})
+	limited.Prefix("/ok").GET(NewTestHandler(http.StatusOK, "ok"))
+
+	for _, path := range []string{"/panic", "/ok"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+
+		if path == "/ok" && rr.Code != http.StatusOK {
+			t.Fatalf("want %d after a panicking request, got %d", http.StatusOK, rr.Code)
+		}
+	}
+}
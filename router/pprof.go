@@ -0,0 +1,29 @@
+package router
+
+import (
+	"net/http"
+	"net/http/pprof"
+
+	"github.com/elmq0022/kami/types"
+)
+
+// EnablePprof registers the net/http/pprof handlers under prefix, relative to the router's prefix:
+// the index at prefix, the cmdline, profile, symbol and trace endpoints, and every named
+// runtime profile (heap, goroutine, allocs, ...) at prefix/:profile. Browse the index with a
+// trailing slash, e.g. /debug/pprof/, so its relative links resolve.
+// Profiling data is sensitive, so pass middleware such as an auth check in mws;
+// it runs inside any middleware added with Use.
+//
+//	r.EnablePprof("/debug/pprof", requireAdmin)
+func (r *Router) EnablePprof(prefix string, mws ...types.Middleware) {
+	p := r.Prefix(prefix)
+	p.GET(FromHTTPHandler(http.HandlerFunc(pprof.Index)), mws...)
+	p.Prefix("/cmdline").GET(FromHTTPHandler(http.HandlerFunc(pprof.Cmdline)), mws...)
+	p.Prefix("/profile").GET(FromHTTPHandler(http.HandlerFunc(pprof.Profile)), mws...)
+	p.Prefix("/symbol").GET(FromHTTPHandler(http.HandlerFunc(pprof.Symbol)), mws...)
+	p.Prefix("/symbol").POST(FromHTTPHandler(http.HandlerFunc(pprof.Symbol)), mws...)
+	p.Prefix("/trace").GET(FromHTTPHandler(http.HandlerFunc(pprof.Trace)), mws...)
+	p.Prefix("/:profile").GET(func(req *http.Request) types.Responder {
+		return FromHTTPHandler(pprof.Handler(GetParams(req.Context())["profile"]))(req)
+	}, mws...)
+}
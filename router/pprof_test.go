@@ -0,0 +1,64 @@
+package router_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/elmq0022/kami/router"
+	"github.com/elmq0022/kami/types"
+)
+
+func TestEnablePprof(t *testing.T) {
+	r, err := router.New()
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+	r.EnablePprof("/debug/pprof")
+
+	t.Run("index", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/pprof/", nil))
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", w.Code)
+		}
+		if !strings.Contains(w.Body.String(), "goroutine") {
+			t.Errorf("expected the profile index, got %q", w.Body.String())
+		}
+	})
+
+	t.Run("named profile", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/pprof/goroutine?debug=1", nil))
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", w.Code)
+		}
+		if !strings.Contains(w.Body.String(), "goroutine profile") {
+			t.Errorf("expected a goroutine dump, got %q", w.Body.String())
+		}
+	})
+}
+
+func TestEnablePprof_Middleware(t *testing.T) {
+	r, err := router.New()
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+	deny := func(next types.Handler) types.Handler {
+		return func(req *http.Request) types.Responder {
+			return &testResponder{Status: http.StatusUnauthorized, Body: "denied"}
+		}
+	}
+	r.EnablePprof("/internal/pprof", deny)
+
+	for _, path := range []string{"/internal/pprof", "/internal/pprof/heap", "/internal/pprof/cmdline"} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("%s: expected status 401, got %d", path, w.Code)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package router
+
+import (
+	"context"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/elmq0022/kami/types"
+)
+
+// RealIPConfig configures the RealIP middleware.
+type RealIPConfig struct {
+	// TrustedProxies lists the proxies allowed to set X-Forwarded-For.
+	// Entries may be IP addresses, CIDR ranges, or hostnames.
+	TrustedProxies []string
+
+	// TTL controls how long resolved hostnames are cached before being looked up again.
+	// Defaults to 5 minutes.
+	TTL time.Duration
+
+	// Resolver looks up the addresses of a hostname.
+	// Defaults to net.DefaultResolver.LookupHost.
+	Resolver func(ctx context.Context, host string) ([]string, error)
+}
+
+// RealIP returns a middleware that replaces req.RemoteAddr with the client address
+// reported by trusted proxies in X-Forwarded-For. The header is only honored when the
+// immediate peer is a trusted proxy, and it is walked from right to left so that
+// addresses appended by untrusted hops cannot spoof the result.
+// Hostname entries are resolved lazily and cached for the configured TTL, so repeated
+// requests do not trigger repeated DNS lookups.
+func RealIP(cfg RealIPConfig) types.Middleware {
+	cache := newProxyCache(cfg)
+
+	return func(next types.Handler) types.Handler {
+		return func(req *http.Request) types.Responder {
+			client := ClientIP(req, cache.get())
+			if client == remoteHost(req) {
+				return next(req)
+			}
+
+			r2 := new(http.Request)
+			*r2 = *req
+			r2.RemoteAddr = client
+			return next(r2)
+		}
+	}
+}
+
+// ClientIP returns the address of the client that sent req. When the immediate peer is one of
+// trustedProxies, X-Forwarded-For is walked from right to left, skipping trusted proxies, and
+// the first untrusted address is returned. The header is ignored when the peer is not trusted,
+// so clients connecting directly cannot spoof their address. Falls back to the host of req.RemoteAddr.
+func ClientIP(req *http.Request, trustedProxies []net.IPNet) string {
+	trusted := func(ip net.IP) bool {
+		for _, n := range trustedProxies {
+			if n.Contains(ip) {
+				return true
+			}
+		}
+		return false
+	}
+
+	peer := remoteHost(req)
+	if ip := net.ParseIP(peer); ip == nil || !trusted(ip) {
+		return peer
+	}
+
+	client := ""
+	hops := strings.Split(strings.Join(req.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		ip := net.ParseIP(strings.TrimSpace(hops[i]))
+		if ip == nil {
+			break
+		}
+		client = ip.String()
+		if !trusted(ip) {
+			break
+		}
+	}
+	if client == "" {
+		return peer
+	}
+	return client
+}
+
+// proxyResolveTimeout bounds each hostname lookup made while refreshing trusted proxies.
+const proxyResolveTimeout = 5 * time.Second
+
+// proxyRetryInterval is how long a failed lookup is cached before it is retried, so an
+// unavailable resolver is not hit by every request.
+const proxyRetryInterval = 10 * time.Second
+
+type proxyCache struct {
+	mu       sync.Mutex
+	static   []net.IPNet
+	hosts    []string
+	ttl      time.Duration
+	resolve  func(ctx context.Context, host string) ([]string, error)
+	lastGood map[string][]net.IPNet
+	resolved []net.IPNet
+	expires  time.Time
+}
+
+func newProxyCache(cfg RealIPConfig) *proxyCache {
+	c := &proxyCache{ttl: cfg.TTL, resolve: cfg.Resolver, lastGood: make(map[string][]net.IPNet)}
+	if c.ttl <= 0 {
+		c.ttl = 5 * time.Minute
+	}
+	if c.resolve == nil {
+		c.resolve = net.DefaultResolver.LookupHost
+	}
+
+	for _, p := range cfg.TrustedProxies {
+		if n := parseIPNet(p); n != nil {
+			c.static = append(c.static, *n)
+		} else {
+			c.hosts = append(c.hosts, p)
+		}
+	}
+	return c
+}
+
+// get returns the trusted networks, resolving hostnames when the cached set has expired.
+// Concurrent callers wait on the same refresh rather than each issuing lookups.
+// Lookups are detached from any request, so a disconnecting client cannot abort a refresh.
+// A failed lookup keeps the last addresses resolved for that host, alongside the static
+// entries, and is retried after proxyRetryInterval rather than on the next request.
+func (c *proxyCache) get() []net.IPNet {
+	if len(c.hosts) == 0 {
+		return c.static
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if now.Before(c.expires) {
+		return c.resolved
+	}
+
+	nets := append([]net.IPNet{}, c.static...)
+	expires := now.Add(c.ttl)
+	for _, host := range c.hosts {
+		ctx, cancel := context.WithTimeout(context.Background(), proxyResolveTimeout)
+		addrs, err := c.resolve(ctx, host)
+		cancel()
+		if err != nil {
+			log.Printf("real ip: resolving trusted proxy %s: %v", host, err)
+			nets = append(nets, c.lastGood[host]...)
+			expires = now.Add(min(c.ttl, proxyRetryInterval))
+			continue
+		}
+
+		var hostNets []net.IPNet
+		for _, a := range addrs {
+			if n := parseIPNet(a); n != nil {
+				hostNets = append(hostNets, *n)
+			}
+		}
+		c.lastGood[host] = hostNets
+		nets = append(nets, hostNets...)
+	}
+
+	c.resolved = nets
+	c.expires = expires
+	return nets
+}
+
+// remoteHost returns the host part of req.RemoteAddr, or the whole value when it has no port.
+func remoteHost(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
+
+func parseIPNet(s string) *net.IPNet {
+	if _, n, err := net.ParseCIDR(s); err == nil {
+		return n
+	}
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil
+	}
+	bits := 8 * net.IPv6len
+	if ip4 := ip.To4(); ip4 != nil {
+		ip, bits = ip4, 8*net.IPv4len
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}
+}
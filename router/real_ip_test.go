@@ -0,0 +1,205 @@
+package router_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/elmq0022/kami/router"
+	"github.com/elmq0022/kami/types"
+)
+
+func newRealIPRouter(t *testing.T, cfg router.RealIPConfig) *router.Router {
+	t.Helper()
+	r, err := router.New()
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+	r.Use(router.RealIP(cfg)).Prefix("/ip").GET(func(req *http.Request) types.Responder {
+		return &testResponder{Status: http.StatusOK, Body: req.RemoteAddr}
+	})
+	return r
+}
+
+func TestRealIP(t *testing.T) {
+	r := newRealIPRouter(t, router.RealIPConfig{TrustedProxies: []string{"10.0.0.0/8"}})
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		xff        string
+		want       string
+	}{
+		{name: "trusted proxy", remoteAddr: "10.0.0.1:1234", xff: "203.0.113.7", want: "203.0.113.7"},
+		{name: "chained trusted proxies", remoteAddr: "10.0.0.1:1234", xff: "203.0.113.7, 10.0.0.2", want: "203.0.113.7"},
+		{name: "spoofed prefix ignored", remoteAddr: "10.0.0.1:1234", xff: "1.1.1.1, 203.0.113.7", want: "203.0.113.7"},
+		{name: "untrusted peer", remoteAddr: "198.51.100.9:1234", xff: "203.0.113.7", want: "198.51.100.9:1234"},
+		{name: "no header", remoteAddr: "10.0.0.1:1234", want: "10.0.0.1:1234"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/ip", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.xff != "" {
+				req.Header.Set("X-Forwarded-For", tt.xff)
+			}
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			if rr.Body.String() != tt.want {
+				t.Fatalf("want %q, got %q", tt.want, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestRealIP_CachesHostnameLookups(t *testing.T) {
+	var calls atomic.Int32
+	resolver := func(ctx context.Context, host string) ([]string, error) {
+		calls.Add(1)
+		return []string{"10.0.0.1"}, nil
+	}
+
+	r := newRealIPRouter(t, router.RealIPConfig{
+		TrustedProxies: []string{"proxy.internal"},
+		TTL:            50 * time.Millisecond,
+		Resolver:       resolver,
+	})
+
+	serve := func() string {
+		req := httptest.NewRequest(http.MethodGet, "/ip", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		req.Header.Set("X-Forwarded-For", "203.0.113.7")
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		return rr.Body.String()
+	}
+
+	for range 5 {
+		if got := serve(); got != "203.0.113.7" {
+			t.Fatalf("want %q, got %q", "203.0.113.7", got)
+		}
+	}
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("expected 1 lookup within the TTL, got %d", got)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	serve()
+	if got := calls.Load(); got != 2 {
+		t.Fatalf("expected a refresh after the TTL, got %d lookups", got)
+	}
+}
+
+func TestRealIP_ResolverFailure(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	var calls atomic.Int32
+	var fail atomic.Bool
+	resolver := func(ctx context.Context, host string) ([]string, error) {
+		calls.Add(1)
+		if _, ok := ctx.Deadline(); !ok {
+			t.Error("expected lookups to run with a timeout")
+		}
+		if fail.Load() {
+			return nil, errors.New("resolver unavailable")
+		}
+		return []string{"192.168.0.1"}, nil
+	}
+
+	r := newRealIPRouter(t, router.RealIPConfig{
+		TrustedProxies: []string{"10.0.0.0/8", "proxy.internal"},
+		TTL:            time.Millisecond,
+		Resolver:       resolver,
+	})
+
+	serve := func(peer string) string {
+		req := httptest.NewRequest(http.MethodGet, "/ip", nil)
+		req.RemoteAddr = peer + ":1234"
+		req.Header.Set("X-Forwarded-For", "203.0.113.7")
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		return rr.Body.String()
+	}
+
+	if got := serve("192.168.0.1"); got != "203.0.113.7" {
+		t.Fatalf("expected the resolved proxy to be trusted, got %q", got)
+	}
+
+	fail.Store(true)
+	time.Sleep(5 * time.Millisecond)
+	if got := serve("10.0.0.1"); got != "203.0.113.7" {
+		t.Fatalf("expected static proxies to stay trusted when lookups fail, got %q", got)
+	}
+	if got := serve("192.168.0.1"); got != "203.0.113.7" {
+		t.Fatalf("expected the last good lookup to stay trusted, got %q", got)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Fatalf("expected the failed lookup to be cached before retrying, got %d lookups", got)
+	}
+}
+
+func TestRealIP_ResolverFailsFirst(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	r := newRealIPRouter(t, router.RealIPConfig{
+		TrustedProxies: []string{"10.0.0.0/8", "proxy.internal"},
+		Resolver: func(ctx context.Context, host string) ([]string, error) {
+			return nil, errors.New("resolver unavailable")
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/ip", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	req.Header.Set("X-Forwarded-For", "203.0.113.7")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	if rr.Body.String() != "203.0.113.7" {
+		t.Fatalf("expected static proxies to be trusted before any successful lookup, got %q", rr.Body.String())
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	_, proxies, _ := net.ParseCIDR("10.0.0.0/8")
+	trusted := []net.IPNet{*proxies}
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		xff        string
+		want       string
+	}{
+		{name: "direct connection", remoteAddr: "198.51.100.9:1234", want: "198.51.100.9"},
+		{name: "single proxy", remoteAddr: "10.0.0.1:1234", xff: "203.0.113.7", want: "203.0.113.7"},
+		{name: "multiple proxies", remoteAddr: "10.0.0.1:1234", xff: "203.0.113.7, 10.0.0.3, 10.0.0.2", want: "203.0.113.7"},
+		{name: "spoofed header from untrusted peer", remoteAddr: "198.51.100.9:1234", xff: "1.1.1.1", want: "198.51.100.9"},
+		{name: "spoofed prefix behind proxy", remoteAddr: "10.0.0.1:1234", xff: "1.1.1.1, 203.0.113.7", want: "203.0.113.7"},
+		{name: "trusted peer without header", remoteAddr: "10.0.0.1:1234", want: "10.0.0.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.xff != "" {
+				req.Header.Set("X-Forwarded-For", tt.xff)
+			}
+
+			if got := router.ClientIP(req, trusted); got != tt.want {
+				t.Fatalf("want %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package router
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/elmq0022/kami/types"
+)
+
+// RecoverPage is the data passed to the RecoverHTML template.
+type RecoverPage struct {
+	Status int
+	// Message is the panic value in dev mode and a generic status text otherwise.
+	Message string
+	// Stack is the goroutine stack at the point of the panic. It is empty unless dev is set.
+	Stack string
+}
+
+// RecoverHTML returns a middleware that recovers panics from the handler or its responder
+// and renders tmpl as a 500 Internal Server Error page. With dev set, the page receives the
+// panic value and stack trace; otherwise it gets a generic message so internals are not leaked.
+// If the template fails to execute, a plain-text 500 is written instead.
+func RecoverHTML(tmpl *template.Template, dev bool) types.Middleware {
+	page := func(req *http.Request, err any) *recoverHTMLResponder {
+		stack := debug.Stack()
+		log.Printf("panic handling %s %s: %v", req.Method, req.URL.Path, err)
+
+		p := RecoverPage{
+			Status:  http.StatusInternalServerError,
+			Message: http.StatusText(http.StatusInternalServerError),
+		}
+		if dev {
+			p.Message = fmt.Sprint(err)
+			p.Stack = string(stack)
+		}
+		return &recoverHTMLResponder{tmpl: tmpl, page: p}
+	}
+
+	return func(next types.Handler) types.Handler {
+		return func(req *http.Request) (responder types.Responder) {
+			defer func() {
+				if err := recover(); err != nil {
+					responder = page(req, err)
+				}
+			}()
+
+			return &recoveringResponder{inner: next(req), recovered: page}
+		}
+	}
+}
+
+type recoveringResponder struct {
+	inner     types.Responder
+	recovered func(*http.Request, any) *recoverHTMLResponder
+}
+
+func (rr *recoveringResponder) Respond(w http.ResponseWriter, req *http.Request) {
+	defer func() {
+		if err := recover(); err != nil {
+			rr.recovered(req, err).Respond(w, req)
+		}
+	}()
+	rr.inner.Respond(w, req)
+}
+
+type recoverHTMLResponder struct {
+	tmpl *template.Template
+	page RecoverPage
+}
+
+func (rh *recoverHTMLResponder) Respond(w http.ResponseWriter, req *http.Request) {
+	var buf bytes.Buffer
+	if err := rh.tmpl.Execute(&buf, rh.page); err != nil {
+		log.Printf("recover html: executing template: %v", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(rh.page.Status)
+	w.Write(buf.Bytes())
+}
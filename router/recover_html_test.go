@@ -0,0 +1,102 @@
+package router_test
+
+import (
+	"bytes"
+	"html/template"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/elmq0022/kami/router"
+	"github.com/elmq0022/kami/types"
+)
+
+var errorPage = template.Must(template.New("500").Parse(
+	`<h1>{{.Status}}</h1><p>{{.Message}}</p>{{if .Stack}}<pre>{{.Stack}}</pre>{{end}}`,
+))
+
+func newRecoverHTMLRouter(t *testing.T, dev bool) *router.Router {
+	t.Helper()
+	r, err := router.New()
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+	r.Use(router.RecoverHTML(errorPage, dev)).Prefix("/boom").GET(func(req *http.Request) types.Responder {
+		panic("database on fire")
+	})
+	return r
+}
+
+func TestRecoverHTML(t *testing.T) {
+	log.SetOutput(&bytes.Buffer{})
+	defer log.SetOutput(os.Stderr)
+
+	tests := []struct {
+		name      string
+		dev       bool
+		wantStack bool
+		wantMsg   string
+	}{
+		{name: "dev", dev: true, wantStack: true, wantMsg: "database on fire"},
+		{name: "prod", dev: false, wantStack: false, wantMsg: "Internal Server Error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newRecoverHTMLRouter(t, tt.dev)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/boom", nil))
+
+			if w.Code != http.StatusInternalServerError {
+				t.Fatalf("expected status %d, got %d", http.StatusInternalServerError, w.Code)
+			}
+			if ct := w.Header().Get("Content-Type"); ct != "text/html; charset=utf-8" {
+				t.Errorf("expected HTML content type, got %q", ct)
+			}
+
+			body := w.Body.String()
+			if !strings.Contains(body, tt.wantMsg) {
+				t.Errorf("expected body to contain %q, got %q", tt.wantMsg, body)
+			}
+			if got := strings.Contains(body, "<pre>"); got != tt.wantStack {
+				t.Errorf("expected stack present=%v, got body %q", tt.wantStack, body)
+			}
+			if !tt.dev && strings.Contains(body, "database on fire") {
+				t.Error("panic value leaked in prod mode")
+			}
+		})
+	}
+}
+
+type panickingResponder struct{}
+
+func (panickingResponder) Respond(w http.ResponseWriter, req *http.Request) {
+	panic("responder failed")
+}
+
+func TestRecoverHTML_ResponderPanic(t *testing.T) {
+	log.SetOutput(&bytes.Buffer{})
+	defer log.SetOutput(os.Stderr)
+
+	r, err := router.New()
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+	r.Use(router.RecoverHTML(errorPage, true)).Prefix("/boom").GET(func(req *http.Request) types.Responder {
+		return panickingResponder{}
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/boom", nil))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "responder failed") {
+		t.Fatalf("expected rendered page, got %q", w.Body.String())
+	}
+}
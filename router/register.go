@@ -0,0 +1,100 @@
+package router
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/elmq0022/kami/types"
+)
+
+// Register adds every route in routes, with each Path taken relative to the router's prefix,
+// so route tables can be declared as data:
+//
+//	r.Prefix("/api").Register(types.Routes{
+//		{Method: http.MethodGet, Path: "/users", Handler: listUsers},
+//		{Method: http.MethodPost, Path: "/users", Handler: createUser},
+//	})
+//
+// The whole table is checked before anything is registered, by first adding it to a copy of the
+// route tree. Panics with a combined message listing every entry that has no method or handler,
+// repeats another entry, is already registered, or has an invalid or conflicting path.
+func (r *Router) Register(routes types.Routes) {
+	var problems []string
+	seen := make(map[string]bool, len(routes))
+	dryRun := r.radix.Clone()
+
+	for i, route := range routes {
+		path := r.Prefix(route.Path).prefix
+		key := route.Method + " " + path
+
+		switch {
+		case route.Method == "":
+			problems = append(problems, fmt.Sprintf("route %d (%s): missing method", i, path))
+		case route.Handler == nil:
+			problems = append(problems, fmt.Sprintf("route %d (%s): missing handler", i, key))
+		case seen[key]:
+			problems = append(problems, fmt.Sprintf("route %d (%s): duplicate entry", i, key))
+		case r.radix.Has(route.Method, path):
+			problems = append(problems, fmt.Sprintf("route %d (%s): already registered", i, key))
+		default:
+			if err := dryRun.AddRoute(route.Method, path, route.Handler); err != nil {
+				problems = append(problems, fmt.Sprintf("route %d (%s): %v", i, key, err))
+			}
+		}
+		seen[key] = true
+	}
+
+	if len(problems) > 0 {
+		panic("cannot register routes: " + strings.Join(problems, "; "))
+	}
+
+	for _, route := range routes {
+		r.add(route.Method, r.Prefix(route.Path).prefix, route.Handler)
+	}
+}
+
+// Batch registers routes atomically. fn receives a staging router with the same prefix and
+// middleware; its routes, scoped not-found handlers, static mounts and global middleware are
+// applied to the router only if fn returns nil. If fn returns an error or a registration panics, for example on a
+// conflicting route, nothing is applied and the error is returned:
+//
+//	err := r.Batch(func(b *router.Router) error {
+//		b.Prefix("/users").GET(listUsers)
+//		b.Prefix("/users/:id").GET(getUser)
+//		return nil
+//	})
+//
+// Routers derived from the staging router must not be kept for registering after Batch returns.
+// Panics if the router has started serving requests or has been frozen.
+func (r *Router) Batch(fn func(*Router) error) (err error) {
+	if r.started.Load() {
+		panic(fmt.Sprintf("cannot register batch under %s since the router is running", r.prefix))
+	}
+	if r.frozen.Load() {
+		panic(fmt.Sprintf("cannot register batch under %s since the router is frozen", r.prefix))
+	}
+
+	mounts := append([]string{}, *r.staticMounts...)
+	scoped := append([]scopedHandler{}, *r.scopedNotFound...)
+	preRoute := append([]types.Middleware{}, *r.preRoute...)
+	staging := r.shallowCopy()
+	staging.radix = r.radix.Clone()
+	staging.staticMounts = &mounts
+	staging.scopedNotFound = &scoped
+	staging.preRoute = &preRoute
+
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("batch registration failed: %v", p)
+		}
+	}()
+	if err := fn(staging); err != nil {
+		return err
+	}
+
+	*r.radix = *staging.radix
+	*r.staticMounts = mounts
+	*r.scopedNotFound = scoped
+	*r.preRoute = preRoute
+	return nil
+}
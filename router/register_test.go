@@ -0,0 +1,199 @@
+package router_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/elmq0022/kami/router"
+	"github.com/elmq0022/kami/types"
+)
+
+func TestRegister(t *testing.T) {
+	r, err := router.New()
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+
+	r.Prefix("/api").Register(types.Routes{
+		{Method: http.MethodGet, Path: "/users", Handler: NewTestHandler(http.StatusOK, "list")},
+		{Method: http.MethodPost, Path: "/users", Handler: NewTestHandler(http.StatusCreated, "create")},
+		{Method: http.MethodGet, Path: "/users/:id", Handler: NewTestHandler(http.StatusOK, "get")},
+	})
+
+	tests := []struct {
+		method     string
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{method: http.MethodGet, path: "/api/users", wantStatus: http.StatusOK, wantBody: "list"},
+		{method: http.MethodPost, path: "/api/users", wantStatus: http.StatusCreated, wantBody: "create"},
+		{method: http.MethodGet, path: "/api/users/7", wantStatus: http.StatusOK, wantBody: "get"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if w.Code != tt.wantStatus || w.Body.String() != tt.wantBody {
+				t.Fatalf("want %d %q, got %d %q", tt.wantStatus, tt.wantBody, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestRegister_InvalidEntriesPanic(t *testing.T) {
+	r, err := router.New()
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+	r.Prefix("/existing").GET(NewTestHandler(http.StatusOK, "ok"))
+
+	defer func() {
+		msg, _ := recover().(string)
+		for _, want := range []string{
+			"route 1 (GET /dup): duplicate entry",
+			"route 2 (GET /existing): already registered",
+			"route 3 (GET /nil): missing handler",
+			"route 4 (/nomethod): missing method",
+		} {
+			if !strings.Contains(msg, want) {
+				t.Errorf("expected panic to mention %q, got %q", want, msg)
+			}
+		}
+
+		// Nothing from the table is registered when it is rejected
+		if _, _, found := r.Match(http.MethodGet, "/dup"); found {
+			t.Error("expected no routes to be registered from an invalid table")
+		}
+	}()
+
+	r.Register(types.Routes{
+		{Method: http.MethodGet, Path: "/dup", Handler: NewTestHandler(http.StatusOK, "a")},
+		{Method: http.MethodGet, Path: "/dup", Handler: NewTestHandler(http.StatusOK, "b")},
+		{Method: http.MethodGet, Path: "/existing", Handler: NewTestHandler(http.StatusOK, "c")},
+		{Method: http.MethodGet, Path: "/nil"},
+		{Path: "/nomethod", Handler: NewTestHandler(http.StatusOK, "d")},
+	})
+}
+
+func TestRegister_InvalidPathsPanicBeforeRegistering(t *testing.T) {
+	r, err := router.New()
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+
+	defer func() {
+		msg, _ := recover().(string)
+		for _, want := range []string{
+			"route 1 (GET /bad/:)",
+			"route 3 (PUT /users/:name): parameter name conflict",
+		} {
+			if !strings.Contains(msg, want) {
+				t.Errorf("expected panic to mention %q, got %q", want, msg)
+			}
+		}
+
+		if _, _, found := r.Match(http.MethodGet, "/ok"); found {
+			t.Error("expected no routes to be registered from an invalid table")
+		}
+	}()
+
+	r.Register(types.Routes{
+		{Method: http.MethodGet, Path: "/ok", Handler: NewTestHandler(http.StatusOK, "ok")},
+		{Method: http.MethodGet, Path: "/bad/:", Handler: NewTestHandler(http.StatusOK, "bad")},
+		{Method: http.MethodGet, Path: "/users/:id", Handler: NewTestHandler(http.StatusOK, "user")},
+		{Method: http.MethodPut, Path: "/users/:name", Handler: NewTestHandler(http.StatusOK, "conflict")},
+	})
+}
+
+func TestBatch_CommitsOnSuccess(t *testing.T) {
+	r, err := router.New()
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+
+	err = r.Prefix("/api").Batch(func(b *router.Router) error {
+		b.Prefix("/users").GET(NewTestHandler(http.StatusOK, "users"))
+		b.Prefix("/users/:id").GET(NewTestHandler(http.StatusOK, "user"))
+		b.UseGlobal(router.MethodOverride())
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := r.Config().GlobalMiddleware; n != 1 {
+		t.Errorf("expected the batch's global middleware to be applied, got %d", n)
+	}
+
+	for _, path := range []string{"/api/users", "/api/users/7"} {
+		if _, _, found := r.Match(http.MethodGet, path); !found {
+			t.Errorf("expected %s to be registered", path)
+		}
+	}
+}
+
+func TestBatch_RollsBackOnFailure(t *testing.T) {
+	tests := []struct {
+		name    string
+		fn      func(b *router.Router) error
+		wantErr string
+	}{
+		{
+			name: "conflicting route",
+			fn: func(b *router.Router) error {
+				b.Prefix("/users").GET(NewTestHandler(http.StatusOK, "users"))
+				b.Prefix("/existing/:name").GET(NewTestHandler(http.StatusOK, "conflict"))
+				return nil
+			},
+			wantErr: "parameter name conflict",
+		},
+		{
+			name: "closure error",
+			fn: func(b *router.Router) error {
+				b.Prefix("/users").GET(NewTestHandler(http.StatusOK, "users"))
+				b.Prefix("/users").NotFound(NewTestHandler(http.StatusNotFound, "scoped"))
+				b.UseGlobal(router.MethodOverride())
+				return errors.New("config missing")
+			},
+			wantErr: "config missing",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := router.New()
+			if err != nil {
+				t.Fatalf("failed to create router: %v", err)
+			}
+			r.Prefix("/existing/:id").GET(NewTestHandler(http.StatusOK, "existing"))
+			before := r.Config()
+
+			err = r.Batch(tt.fn)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+
+			if _, _, found := r.Match(http.MethodGet, "/users"); found {
+				t.Error("expected /users not to be registered after a failed batch")
+			}
+			after := r.Config()
+			if after.Routes != before.Routes {
+				t.Errorf("expected %d routes, got %d", before.Routes, after.Routes)
+			}
+			if after.GlobalMiddleware != before.GlobalMiddleware {
+				t.Errorf("expected %d global middleware, got %d", before.GlobalMiddleware, after.GlobalMiddleware)
+			}
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/missing", nil))
+			if w.Body.String() != "Not Found" {
+				t.Errorf("expected the default not-found handler, got %q", w.Body.String())
+			}
+		})
+	}
+}
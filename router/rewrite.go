@@ -0,0 +1,120 @@
+package router
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/elmq0022/kami/types"
+)
+
+type rewriteRule struct {
+	from   []string
+	to     []string
+	static int
+}
+
+// RewritePath returns a middleware that maps legacy path patterns to new ones before routing.
+// Keys and values use the router's path syntax, so parameters captured in the old
+// pattern can be substituted into the new one:
+//
+//	router.RewritePath(map[string]string{"/v1/u/:id": "/v2/users/:id"})
+//
+// When several rules match, the one with the most static segments wins.
+// Rewriting only affects routing when installed with UseGlobal or WithPreRoute.
+// Panics if a target references a parameter that its source pattern does not capture.
+func RewritePath(rules map[string]string) types.Middleware {
+	compiled := make([]rewriteRule, 0, len(rules))
+	for from, to := range rules {
+		rule := rewriteRule{from: splitPath(from), to: splitPath(to)}
+
+		captured := make(map[string]bool)
+		for _, seg := range rule.from {
+			if isParamSegment(seg) {
+				captured[seg[1:]] = true
+			} else {
+				rule.static++
+			}
+		}
+		for _, seg := range rule.to {
+			if isParamSegment(seg) && !captured[seg[1:]] {
+				panic(fmt.Sprintf("rewrite %s -> %s: parameter %s is not captured", from, to, seg[1:]))
+			}
+		}
+
+		compiled = append(compiled, rule)
+	}
+
+	sort.Slice(compiled, func(i, j int) bool {
+		if compiled[i].static != compiled[j].static {
+			return compiled[i].static > compiled[j].static
+		}
+		return strings.Join(compiled[i].from, "/") < strings.Join(compiled[j].from, "/")
+	})
+
+	return func(next types.Handler) types.Handler {
+		return func(req *http.Request) types.Responder {
+			segments := splitPath(req.URL.Path)
+			for _, rule := range compiled {
+				if path, ok := rule.apply(segments); ok {
+					r2 := new(http.Request)
+					*r2 = *req
+					r2.URL = new(url.URL)
+					*r2.URL = *req.URL
+					r2.URL.Path = path
+					r2.URL.RawPath = ""
+					return next(r2)
+				}
+			}
+			return next(req)
+		}
+	}
+}
+
+func (rule rewriteRule) apply(segments []string) (string, bool) {
+	params := make(map[string]string)
+	for i, seg := range rule.from {
+		switch {
+		case seg[0] == '*':
+			params[seg[1:]] = strings.Join(segments[min(i, len(segments)):], "/")
+		case i >= len(segments):
+			return "", false
+		case seg[0] == ':':
+			params[seg[1:]] = segments[i]
+		case seg != segments[i]:
+			return "", false
+		}
+	}
+
+	if n := len(rule.from); n == 0 || rule.from[n-1][0] != '*' {
+		if len(segments) != n {
+			return "", false
+		}
+	}
+
+	out := make([]string, len(rule.to))
+	for i, seg := range rule.to {
+		if isParamSegment(seg) {
+			out[i] = params[seg[1:]]
+		} else {
+			out[i] = seg
+		}
+	}
+	return "/" + strings.Join(out, "/"), true
+}
+
+func splitPath(path string) []string {
+	var segments []string
+	for _, seg := range strings.Split(path, "/") {
+		if seg != "" {
+			segments = append(segments, seg)
+		}
+	}
+	return segments
+}
+
+func isParamSegment(seg string) bool {
+	return len(seg) > 1 && (seg[0] == ':' || seg[0] == '*')
+}
@@ -0,0 +1,66 @@
+package router_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/elmq0022/kami/router"
+	"github.com/elmq0022/kami/types"
+)
+
+func TestRewritePath(t *testing.T) {
+	rules := map[string]string{
+		"/v1/u/:id":       "/v2/users/:id",
+		"/v1/u/me":        "/v2/profile",
+		"/v1/files/*path": "/v2/assets/*path",
+	}
+
+	r, _ := router.New(router.WithPreRoute(router.RewritePath(rules)))
+
+	echo := func(req *http.Request) types.Responder {
+		params := router.GetParams(req.Context())
+		return &testResponder{Status: http.StatusOK, Body: req.URL.Path + " " + params["id"] + params["path"]}
+	}
+	r.Prefix("/v2/users/:id").GET(echo)
+	r.Prefix("/v2/profile").GET(echo)
+	r.Prefix("/v2/assets/*path").GET(echo)
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "param substitution", path: "/v1/u/42", wantStatus: http.StatusOK, wantBody: "/v2/users/42 42"},
+		{name: "static rule wins", path: "/v1/u/me", wantStatus: http.StatusOK, wantBody: "/v2/profile "},
+		{name: "wildcard substitution", path: "/v1/files/css/site.css", wantStatus: http.StatusOK, wantBody: "/v2/assets/css/site.css css/site.css"},
+		{name: "new path untouched", path: "/v2/users/7", wantStatus: http.StatusOK, wantBody: "/v2/users/7 7"},
+		{name: "extra segments do not match", path: "/v1/u/42/extra", wantStatus: http.StatusNotFound, wantBody: "Not Found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			r.ServeHTTP(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("want %d, got %d", tt.wantStatus, rr.Code)
+			}
+			if rr.Body.String() != tt.wantBody {
+				t.Fatalf("want %q, got %q", tt.wantBody, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestRewritePath_UncapturedParamPanics(t *testing.T) {
+	defer func() {
+		if rec := recover(); rec == nil {
+			t.Fatal("expected panic for uncaptured parameter, got nil")
+		}
+	}()
+
+	router.RewritePath(map[string]string{"/v1/u/:id": "/v2/users/:uid"})
+}
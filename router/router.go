@@ -19,11 +19,26 @@ import (
 // Router is the main HTTP router that uses a radix tree for efficient route matching.
 // It supports middleware, custom 404 handlers, and panic recovery.
 type Router struct {
-	radix      *radix.Radix
-	notFound   types.Handler
-	middleware []types.Middleware
-	started    *atomic.Bool
-	prefix     string
+	radix          *radix.Radix
+	notFound       types.Handler
+	customNotFound bool
+	notAllowed     types.Handler
+	globalOptions  types.Handler
+	errorHandler   ErrorHandler
+	panicHandler   PanicHandler
+	middleware     []types.Middleware
+	tags           []string
+	preRoute       *[]types.Middleware
+	staticMounts   *[]string
+	scopedNotFound *[]scopedHandler
+	skipped        *skipLog
+	skipCanceled   bool
+	defaultHeader  http.Header
+	wrappers       []func(http.Handler) http.Handler
+	maxPathLength  int
+	started        *atomic.Bool
+	frozen         *atomic.Bool
+	prefix         string
 }
 
 // New creates a new Router with the given options.
@@ -36,9 +51,14 @@ func New(opts ...Option) (*Router, error) {
 	}
 
 	r := &Router{
-		radix:    rdx,
-		notFound: handlers.DefaultNotFoundHandler,
-		started:  &atomic.Bool{},
+		radix:          rdx,
+		notFound:       handlers.DefaultNotFoundHandler,
+		preRoute:       &[]types.Middleware{},
+		staticMounts:   &[]string{},
+		scopedNotFound: &[]scopedHandler{},
+		skipped:        &skipLog{},
+		started:        &atomic.Bool{},
+		frozen:         &atomic.Bool{},
 	}
 
 	for _, opt := range opts {
@@ -55,19 +75,48 @@ func New(opts ...Option) (*Router, error) {
 func (r *Router) Run(port string) {
 	r.started.Store(true)
 	log.Printf("Starting server on %s", port)
-	if err := http.ListenAndServe(port, r); err != nil {
+	if err := http.ListenAndServe(port, r.Handler()); err != nil {
 		log.Fatalf("Server failed to start: %v", err)
 	}
 }
 
+// Handler returns the router wrapped with its server-level options: default headers are set
+// first, then any handler wrappers run, outermost first, around ServeHTTP (which applies
+// pre-route middleware). Run serves this handler, so tests and embedders using it see
+// the same behavior as a running server.
+func (r *Router) Handler() http.Handler {
+	var h http.Handler = r
+	for i := len(r.wrappers) - 1; i >= 0; i-- {
+		h = r.wrappers[i](h)
+	}
+
+	if len(r.defaultHeader) == 0 {
+		return h
+	}
+	inner := h
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		for k, v := range r.defaultHeader {
+			w.Header()[k] = append([]string(nil), v...)
+		}
+		inner.ServeHTTP(w, req)
+	})
+}
+
 // ServeHTTP implements http.Handler, making Router compatible with the standard library.
-// It performs route lookup, applies middleware, handles panics, and executes the matched handler.
+// It runs any pre-route middleware, performs route lookup, handles panics, and executes the matched handler.
+// With WithSkipCanceled, the response is discarded if the request context is canceled by the
+// time the handler returns, typically because the client disconnected.
 // If no route matches, the configured notFound handler is used (defaults to a 404 response).
+// Paths longer than the WithMaxPathLength limit are rejected with 414 before anything else runs.
 func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	r.started.Store(true)
 
 	defer func() {
 		if err := recover(); err != nil {
+			if r.panicHandler != nil {
+				r.panicHandler(w, req, err)
+				return
+			}
 			log.Printf("panic handling %s %s: %v", req.Method, req.URL.Path, err)
 			http.Error(
 				w,
@@ -77,118 +126,306 @@ func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		}
 	}()
 
-	h, params, ok := r.radix.Lookup(req.Method, req.URL.Path)
-	if !ok {
-		h = r.notFound
-		params = map[string]string{}
+	// Reject oversized paths before any middleware or tree traversal sees them
+	if r.maxPathLength > 0 && len(req.URL.Path) > r.maxPathLength {
+		responders.JSONErrorResponse(http.StatusText(http.StatusRequestURITooLong), http.StatusRequestURITooLong).Respond(w, req)
+		return
 	}
 
-	ctx := WithParams(req.Context(), params)
-	req = req.WithContext(ctx)
+	// Pre-route middleware wraps the whole dispatch so it can alter the request before lookup
+	h := types.Handler(r.dispatch)
+	pre := *r.preRoute
+	for i := len(pre) - 1; i >= 0; i-- {
+		h = pre[i](h)
+	}
 
 	responder := h(req)
+
+	// Discard output for clients that have already gone away, but still run the responder
+	// so middleware that releases resources or observes the response in Respond sees it
+	if r.skipCanceled && req.Context().Err() != nil {
+		r.skipped.record(req)
+		w = &canceledWriter{header: make(http.Header)}
+	}
 	responder.Respond(w, req)
 }
 
-func (r *Router) add(method string, handler types.Handler) {
+// Match reports which route method and path resolve to, without executing the handler.
+// It returns the matched route pattern (e.g. "/users/:id") and the extracted params.
+// Match is read-only and does not mark the router as started, which makes it suitable
+// for asserting a route table in tests.
+func (r *Router) Match(method, path string) (pattern string, params map[string]string, found bool) {
+	_, params, pattern, found = r.radix.Match(method, path)
+	if params == nil {
+		params = map[string]string{}
+	}
+	return pattern, params, found
+}
+
+// dispatch looks up the route for req and executes the matched handler.
+// The returned responder is bound to the routed request so it sees the matched
+// params even when pre-route middleware calls Respond with the original request.
+func (r *Router) dispatch(req *http.Request) types.Responder {
+	ctx := req.Context()
+	h, params, pattern, ok := r.radix.Match(req.Method, req.URL.Path)
+	if !ok {
+		allowed := r.radix.Methods(req.URL.Path)
+		h = r.notFoundFor(req.URL.Path)
+		if len(allowed) > 0 && r.notAllowed != nil {
+			h = withAllowHeader(r.notAllowed, allowed)
+		}
+		if req.Method == http.MethodOptions && r.globalOptions != nil {
+			h = r.globalOptions
+		}
+		params = nil
+		ctx = WithAllowedMethods(ctx, allowed)
+	}
+
+	if name, ok := wildcardName(pattern); ok {
+		// Capture the remainder as sent so proxies can forward the original bytes, keeping the
+		// decoded value when the wildcard starts inside an escaped segment
+		if raw, ok := rawRemainder(req.URL.EscapedPath(), strings.Count(pattern, "/")-1); ok {
+			params[name] = raw
+		}
+		ctx = withWildcard(ctx, name, params[name])
+	}
+
+	ctx = WithParams(ctx, params)
+	ctx = withRoutePattern(ctx, pattern)
+	if r.errorHandler != nil {
+		ctx = withErrorHandler(ctx, r.errorHandler)
+	}
+	req = req.WithContext(ctx)
+
+	return &routedResponder{inner: h(req), req: req}
+}
+
+type routedResponder struct {
+	inner types.Responder
+	req   *http.Request
+}
+
+func (rr *routedResponder) Respond(w http.ResponseWriter, _ *http.Request) {
+	rr.inner.Respond(w, rr.req)
+}
+
+// add registers handler for method at path, wrapped by the router's middleware and then
+// any route-specific middleware. Router middleware is outermost, so the full chain is
+// r.middleware[0] -> ... -> mws[0] -> ... -> handler.
+func (r *Router) add(method, path string, handler types.Handler, mws ...types.Middleware) {
 	if r.started.Load() {
-		panic(fmt.Sprintf("cannot register path: %s since the router is running", r.prefix))
+		panic(fmt.Sprintf("cannot register path: %s since the router is running", path))
 	}
+	if r.frozen.Load() {
+		panic(fmt.Sprintf("cannot register path: %s since the router is frozen", path))
+	}
+
+	chain := append(append([]types.Middleware{}, r.middleware...), mws...)
 
-	// Apply route-specific middleware in reverse order at registration time
+	// Wrap from the innermost middleware outwards so chain[0] runs first
 	h := handler
-	for i := len(r.middleware) - 1; i >= 0; i-- {
-		h = r.middleware[i](h)
+	for i := len(chain) - 1; i >= 0; i-- {
+		h = chain[i](h)
+	}
+	if len(r.tags) > 0 {
+		h = withTags(h, append([]string{}, r.tags...))
 	}
 
-	if err := r.radix.AddRoute(method, r.prefix, h); err != nil {
-		panic(fmt.Sprintf("%s %s: %v", method, r.prefix, err))
+	if err := r.radix.AddRoute(method, path, h); err != nil {
+		panic(fmt.Sprintf("%s %s: %v", method, path, err))
 	}
 }
 
+// Handle registers a handler for the given method at path, relative to the router's current prefix.
+// Any middleware passed runs inside the router's middleware, for this route only.
+// It is the path-style counterpart to the verb methods:
+//
+//	r.Handle(http.MethodGet, "/users/:id", getUser, auditMiddleware)
+//
+// Panics if the route cannot be registered (e.g., conflicts with existing routes).
+func (r *Router) Handle(method, path string, handler types.Handler, mws ...types.Middleware) {
+	r.add(method, r.Prefix(path).prefix, handler, mws...)
+}
+
 // GET registers a handler for GET requests at the router's current prefix path.
 // The prefix can include parameters (e.g., "/users/:id") and wildcards (e.g., "/files/*filepath").
+// Any middleware passed runs inside the router's middleware, for this route only.
 // Panics if the route cannot be registered (e.g., conflicts with existing routes).
-func (r *Router) GET(handler types.Handler) {
-	r.add(http.MethodGet, handler)
+func (r *Router) GET(handler types.Handler, mws ...types.Middleware) {
+	r.add(http.MethodGet, r.prefix, handler, mws...)
 }
 
 // POST registers a handler for POST requests at the router's current prefix path.
 // The prefix can include parameters (e.g., "/users/:id") and wildcards (e.g., "/files/*filepath").
+// Any middleware passed runs inside the router's middleware, for this route only.
 // Panics if the route cannot be registered (e.g., conflicts with existing routes).
-func (r *Router) POST(handler types.Handler) {
-	r.add(http.MethodPost, handler)
+func (r *Router) POST(handler types.Handler, mws ...types.Middleware) {
+	r.add(http.MethodPost, r.prefix, handler, mws...)
 }
 
 // PUT registers a handler for PUT requests at the router's current prefix path.
 // The prefix can include parameters (e.g., "/users/:id") and wildcards (e.g., "/files/*filepath").
+// Any middleware passed runs inside the router's middleware, for this route only.
 // Panics if the route cannot be registered (e.g., conflicts with existing routes).
-func (r *Router) PUT(handler types.Handler) {
-	r.add(http.MethodPut, handler)
+func (r *Router) PUT(handler types.Handler, mws ...types.Middleware) {
+	r.add(http.MethodPut, r.prefix, handler, mws...)
 }
 
 // DELETE registers a handler for DELETE requests at the router's current prefix path.
 // The prefix can include parameters (e.g., "/users/:id") and wildcards (e.g., "/files/*filepath").
+// Any middleware passed runs inside the router's middleware, for this route only.
 // Panics if the route cannot be registered (e.g., conflicts with existing routes).
-func (r *Router) DELETE(handler types.Handler) {
-	r.add(http.MethodDelete, handler)
+func (r *Router) DELETE(handler types.Handler, mws ...types.Middleware) {
+	r.add(http.MethodDelete, r.prefix, handler, mws...)
 }
 
 // PATCH registers a handler for PATCH requests at the router's current prefix path.
 // The prefix can include parameters (e.g., "/users/:id") and wildcards (e.g., "/files/*filepath").
+// Any middleware passed runs inside the router's middleware, for this route only.
 // Panics if the route cannot be registered (e.g., conflicts with existing routes).
-func (r *Router) PATCH(handler types.Handler) {
-	r.add(http.MethodPatch, handler)
+func (r *Router) PATCH(handler types.Handler, mws ...types.Middleware) {
+	r.add(http.MethodPatch, r.prefix, handler, mws...)
 }
 
 // HEAD registers a handler for HEAD requests at the router's current prefix path.
 // The prefix can include parameters (e.g., "/users/:id") and wildcards (e.g., "/files/*filepath").
+// Any middleware passed runs inside the router's middleware, for this route only.
 // Panics if the route cannot be registered (e.g., conflicts with existing routes).
-func (r *Router) HEAD(handler types.Handler) {
-	r.add(http.MethodHead, handler)
+func (r *Router) HEAD(handler types.Handler, mws ...types.Middleware) {
+	r.add(http.MethodHead, r.prefix, handler, mws...)
 }
 
 // OPTIONS registers a handler for OPTIONS requests at the router's current prefix path.
 // The prefix can include parameters (e.g., "/users/:id") and wildcards (e.g., "/files/*filepath").
+// Any middleware passed runs inside the router's middleware, for this route only.
 // Panics if the route cannot be registered (e.g., conflicts with existing routes).
-func (r *Router) OPTIONS(handler types.Handler) {
-	r.add(http.MethodOptions, handler)
+func (r *Router) OPTIONS(handler types.Handler, mws ...types.Middleware) {
+	r.add(http.MethodOptions, r.prefix, handler, mws...)
 }
 
 // CONNECT registers a handler for CONNECT requests at the router's current prefix path.
 // The prefix can include parameters (e.g., "/users/:id") and wildcards (e.g., "/files/*filepath").
+// Any middleware passed runs inside the router's middleware, for this route only.
 // Panics if the route cannot be registered (e.g., conflicts with existing routes).
-func (r *Router) CONNECT(handler types.Handler) {
-	r.add(http.MethodConnect, handler)
+func (r *Router) CONNECT(handler types.Handler, mws ...types.Middleware) {
+	r.add(http.MethodConnect, r.prefix, handler, mws...)
 }
 
 // TRACE registers a handler for TRACE requests at the router's current prefix path.
 // The prefix can include parameters (e.g., "/users/:id") and wildcards (e.g., "/files/*filepath").
+// Any middleware passed runs inside the router's middleware, for this route only.
 // Panics if the route cannot be registered (e.g., conflicts with existing routes).
-func (r *Router) TRACE(handler types.Handler) {
-	r.add(http.MethodTrace, handler)
+func (r *Router) TRACE(handler types.Handler, mws ...types.Middleware) {
+	r.add(http.MethodTrace, r.prefix, handler, mws...)
+}
+
+// AnyMethods are the methods ANY and HandleAny register a handler for.
+var AnyMethods = []string{
+	http.MethodGet,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+	http.MethodHead,
+	http.MethodOptions,
+}
+
+// ANY registers a handler for every method in AnyMethods at the router's current prefix path.
+// Any middleware passed runs inside the router's middleware, for these routes only.
+// Panics if a handler is already registered for one of the methods at this path.
+func (r *Router) ANY(handler types.Handler, mws ...types.Middleware) {
+	r.addAny(r.prefix, handler, mws...)
+}
+
+// HandleAny is the path-style counterpart to ANY.
+func (r *Router) HandleAny(path string, handler types.Handler, mws ...types.Middleware) {
+	r.addAny(r.Prefix(path).prefix, handler, mws...)
+}
+
+func (r *Router) addAny(path string, handler types.Handler, mws ...types.Middleware) {
+	for _, method := range AnyMethods {
+		if r.radix.Has(method, path) {
+			panic(fmt.Sprintf("ANY %s: a %s handler is already registered", path, method))
+		}
+	}
+	for _, method := range AnyMethods {
+		r.add(method, path, handler, mws...)
+	}
+}
+
+// Freeze permanently locks route registration on the router and every router derived from it.
+// Registration is also locked implicitly once the router serves its first request;
+// calling Freeze before Run makes that lifecycle explicit and catches late registrations
+// at startup rather than on first traffic.
+func (r *Router) Freeze() {
+	r.frozen.Store(true)
+}
+
+// IsFrozen reports whether Freeze has been called on the router or any router sharing its routes.
+func (r *Router) IsFrozen() bool {
+	return r.frozen.Load()
 }
 
 func (r *Router) shallowCopy() *Router {
 	nr := Router{
-		radix:      r.radix,
-		notFound:   r.notFound,
-		prefix:     r.prefix,
-		started:    r.started,
-		middleware: append([]types.Middleware{}, r.middleware...),
+		radix:          r.radix,
+		notFound:       r.notFound,
+		customNotFound: r.customNotFound,
+		notAllowed:     r.notAllowed,
+		globalOptions:  r.globalOptions,
+		errorHandler:   r.errorHandler,
+		panicHandler:   r.panicHandler,
+		prefix:         r.prefix,
+		preRoute:       r.preRoute,
+		staticMounts:   r.staticMounts,
+		scopedNotFound: r.scopedNotFound,
+		skipped:        r.skipped,
+		skipCanceled:   r.skipCanceled,
+		defaultHeader:  r.defaultHeader,
+		wrappers:       r.wrappers,
+		maxPathLength:  r.maxPathLength,
+		started:        r.started,
+		frozen:         r.frozen,
+		middleware:     append([]types.Middleware{}, r.middleware...),
+		tags:           append([]string{}, r.tags...),
 	}
 	return &nr
 }
 
 // Use adds one or more middleware to the router's global middleware chain.
-// Middleware is applied to all routes in the order it is registered.
-// Multiple calls to Use will append middleware to the chain.
+// Middleware is applied to all routes in the order it is registered: the first middleware
+// is outermost, so Use(a, b, c) runs a, then b, then c before the handler, and sees the
+// responder last on the way out. Multiple calls to Use will append middleware to the chain.
 func (r *Router) Use(mws ...types.Middleware) *Router {
 	nr := r.shallowCopy()
 	nr.middleware = append(nr.middleware, mws...)
 	return nr
 }
 
+// Tag returns a router whose routes carry the given tags in addition to any existing ones.
+// Tags are route metadata that middleware can inspect at serve time with GetRouteTags or IfTag.
+func (r *Router) Tag(tags ...string) *Router {
+	nr := r.shallowCopy()
+	nr.tags = append(nr.tags, tags...)
+	return nr
+}
+
+// UseGlobal adds middleware that wraps the router's entire dispatch.
+// Unlike Use, which scopes middleware to the routes registered through the returned router,
+// global middleware applies to every request, including ones that end in the not-found handler.
+// It runs before the route lookup, so it can alter the request in ways that affect routing.
+// The full order is: global middleware -> lookup -> route middleware -> handler.
+// Panics if called after the router has started serving requests or has been frozen.
+func (r *Router) UseGlobal(mws ...types.Middleware) {
+	if r.started.Load() {
+		panic("cannot add global middleware since the router is running")
+	}
+	if r.frozen.Load() {
+		panic("cannot add global middleware since the router is frozen")
+	}
+	*r.preRoute = append(*r.preRoute, mws...)
+}
+
 func (r *Router) Prefix(segment string) *Router {
 	if segment == "" {
 		return r.shallowCopy() // no change
@@ -204,13 +441,59 @@ func (r *Router) Prefix(segment string) *Router {
 	return nr
 }
 
+// Group calls fn with a copy of the router scoped to the given prefix, for registering
+// related routes together:
+//
+//	r.Group("/api/v1", func(g *router.Router) {
+//		g = g.Use(authMiddleware)
+//		g.Prefix("/users").GET(listUsers)
+//		g.Prefix("/users/:id").GET(getUser)
+//	})
+//
+// Because Use and Prefix return new routers, middleware added inside the closure
+// applies only to the group's routes and never leaks to the parent.
+func (r *Router) Group(prefix string, fn func(g *Router)) {
+	fn(r.Prefix(prefix))
+}
+
+// Include applies a reusable route module to the router's current scope: fn's registrations
+// land under the router's prefix with its middleware. The same module can be included under
+// several parents:
+//
+//	func userRoutes(r *router.Router) {
+//		r.Prefix("/users").GET(listUsers)
+//	}
+//
+//	r.Prefix("/v1").Include(userRoutes)
+//	r.Prefix("/v2").Use(deprecation).Include(userRoutes)
+//
+// fn receives a copy of the router, so middleware it adds with Use does not leak back.
+func (r *Router) Include(fn func(*Router)) {
+	fn(r.shallowCopy())
+}
+
 // ServeStatic registers a handler to serve static files from the given filesystem.
 // The router's current prefix determines the URL path where files will be served.
 // For example, r.Prefix("/static").ServeStatic(os.DirFS("./static")) serves files from
 // the ./static directory at /static/*.
-// Automatically handles directory redirects and delegates to http.FileServer.
-func (r *Router) ServeStatic(f fs.FS) {
-	staticResponder := responders.NewStaticDirResponder(f, r.prefix)
+// Automatically handles directory redirects and supports Range requests.
+// An optional responders.StaticOptions configures caching headers for served files.
+// ServeStatic may be called on several prefixes, e.g. /static and /uploads; each mount
+// registers its own wildcard route under its prefix, so the mounts do not conflict.
+func (r *Router) ServeStatic(f fs.FS, opts ...responders.StaticOptions) {
+	if len(opts) > 0 && opts[0].FallThrough && opts[0].NotFound == nil {
+		opts = append([]responders.StaticOptions{}, opts...)
+		opts[0].NotFound = func(req *http.Request) types.Responder {
+			return r.notFoundFor(req.URL.Path)(req)
+		}
+	}
+	staticResponder := responders.NewStaticDirResponder(f, r.prefix, opts...)
+
+	mount := r.prefix
+	if mount == "" {
+		mount = "/"
+	}
+	*r.staticMounts = append(*r.staticMounts, mount)
 
 	// Add wildcard pattern for file paths and register handler
 	r.Prefix("/*fp").GET(func(req *http.Request) types.Responder {
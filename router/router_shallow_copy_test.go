@@ -116,9 +116,8 @@ func TestMiddlewareChaining_OrderIsCorrect(t *testing.T) {
 	rr := httptest.NewRecorder()
 	r3.ServeHTTP(rr, req)
 
-	// Middleware wraps in reverse during add (lines 102-104 in router.go)
-	// So mw1 wraps mw2 wraps mw3 wraps handler
-	// Result: 3, 2, 1 are appended in that order
+	// mw1 wraps mw2 wraps mw3 wraps handler, so mw1 runs first.
+	// Each appends after next returns, so the body reads 3, 2, 1
 	want := "321"
 	got := rr.Body.String()
 	if got != want {
@@ -600,3 +599,86 @@ func TestPrefix_ReturnsNewRouter(t *testing.T) {
 		t.Error("r2 and r3 should be different instances")
 	}
 }
+
+// TestGroup_ScopesPrefixAndMiddleware verifies Group registers under the prefix and isolates middleware
+func TestGroup_ScopesPrefixAndMiddleware(t *testing.T) {
+	r, err := router.New()
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+
+	r.Group("/api/v1/", func(g *router.Router) {
+		g = g.Use(testMiddleware1)
+		g.Prefix("/users").GET(testHandler)
+		g.Prefix("/posts").GET(testHandler)
+	})
+	r.Prefix("/outside").GET(testHandler)
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/api/v1/users", "1"},
+		{"/api/v1/posts", "1"},
+		{"/outside", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			if rr.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d", rr.Code)
+			}
+			if rr.Body.String() != tt.want {
+				t.Errorf("want %q, got %q", tt.want, rr.Body.String())
+			}
+		})
+	}
+
+	if got := r.Config().Middleware; got != 0 {
+		t.Errorf("parent middleware should be untouched, got %d", got)
+	}
+}
+
+// TestInclude_AppliesModuleUnderEachParent verifies one module function registers under several scopes
+func TestInclude_AppliesModuleUnderEachParent(t *testing.T) {
+	r, err := router.New()
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+
+	module := func(m *router.Router) {
+		m.Prefix("/users").GET(testHandler)
+		m.Prefix("/users/:id").GET(testHandler)
+	}
+	r.Prefix("/v1").Include(module)
+	r.Prefix("/v2").Use(testMiddleware2).Include(module)
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/v1/users", ""},
+		{"/v1/users/7", ""},
+		{"/v2/users", "2"},
+		{"/v2/users/7", "2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			if rr.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d", rr.Code)
+			}
+			if rr.Body.String() != tt.want {
+				t.Errorf("want %q, got %q", tt.want, rr.Body.String())
+			}
+		})
+	}
+}
@@ -1,11 +1,21 @@
 package router_test
 
 import (
+	"bytes"
+	"context"
+	"io"
+	"log"
 	"maps"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"testing/fstest"
 
+	"github.com/elmq0022/kami/responders"
 	"github.com/elmq0022/kami/router"
 	"github.com/elmq0022/kami/types"
 )
@@ -111,3 +121,533 @@ func TestRouter_CannotAddRoutesAfterStarted(t *testing.T) {
 
 	r.Prefix("/after").GET(NewTestHandler(http.StatusOK, "after"))
 }
+
+func TestRouter_UseGlobal(t *testing.T) {
+	r, err := router.New()
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+
+	var order []string
+	var globalParams map[string]string
+
+	global := func(next types.Handler) types.Handler {
+		return func(req *http.Request) types.Responder {
+			order = append(order, "global")
+			globalParams = router.GetParams(req.Context())
+			req.URL.Path = "/users/42"
+			return next(req)
+		}
+	}
+	route := func(next types.Handler) types.Handler {
+		return func(req *http.Request) types.Responder {
+			order = append(order, "route")
+			return next(req)
+		}
+	}
+
+	r.UseGlobal(global)
+	r.Prefix("/users/:id").Use(route).GET(func(req *http.Request) types.Responder {
+		order = append(order, "handler")
+		return &testResponder{Status: http.StatusOK, Body: router.GetParams(req.Context())["id"]}
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/anything", nil)
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: want %d, got %d", http.StatusOK, rr.Code)
+	}
+	if rr.Body.String() != "42" {
+		t.Fatalf("body: want %q, got %q", "42", rr.Body.String())
+	}
+	if len(globalParams) != 0 {
+		t.Fatalf("global middleware should run before params are resolved, got %v", globalParams)
+	}
+	if want := []string{"global", "route", "handler"}; !slices.Equal(order, want) {
+		t.Fatalf("order: want %v, got %v", want, order)
+	}
+}
+
+func TestRouter_UseGlobalRunsOnNotFound(t *testing.T) {
+	r, _ := router.New()
+
+	called := false
+	r.UseGlobal(func(next types.Handler) types.Handler {
+		return func(req *http.Request) types.Responder {
+			called = true
+			return next(req)
+		}
+	})
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("status: want %d, got %d", http.StatusNotFound, rr.Code)
+	}
+	if !called {
+		t.Fatal("expected global middleware to run for unmatched routes")
+	}
+}
+
+func TestRouter_Freeze(t *testing.T) {
+	r, err := router.New()
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+	api := r.Prefix("/api")
+
+	if r.IsFrozen() {
+		t.Fatal("router should not be frozen initially")
+	}
+
+	// Registration before freezing works
+	api.Prefix("/before").GET(NewTestHandler(http.StatusOK, "before"))
+	r.Freeze()
+
+	if !r.IsFrozen() || !api.IsFrozen() {
+		t.Fatal("expected router and derived routers to report frozen")
+	}
+
+	defer func() {
+		if rec := recover(); rec == nil {
+			t.Fatal("expected panic when adding route after freeze, got nil")
+		} else {
+			panicMsg := rec.(string)
+			expectedMsg := "cannot register path: /api/after since the router is frozen"
+			if panicMsg != expectedMsg {
+				t.Fatalf("unexpected panic message: got %q, want %q", panicMsg, expectedMsg)
+			}
+		}
+
+		// Routes registered before freezing still serve
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/before", nil))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("status: want %d, got %d", http.StatusOK, rr.Code)
+		}
+	}()
+
+	api.Prefix("/after").GET(NewTestHandler(http.StatusOK, "after"))
+}
+
+func TestRouter_EmptyPathMatchesRoot(t *testing.T) {
+	r, err := router.New()
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+	r.Prefix("/").GET(NewTestHandler(http.StatusOK, "root"))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.URL.Path = ""
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: want %d, got %d", http.StatusOK, rr.Code)
+	}
+	if rr.Body.String() != "root" {
+		t.Fatalf("body: want %q, got %q", "root", rr.Body.String())
+	}
+}
+
+func TestRouter_RoutePattern(t *testing.T) {
+	r, err := router.New()
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+
+	var got string
+	r.Prefix("/users/:id").GET(func(req *http.Request) types.Responder {
+		got = router.GetRoutePattern(req.Context())
+		return &testResponder{Status: http.StatusOK, Body: "ok"}
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/42", nil))
+
+	if got != "/users/:id" {
+		t.Fatalf("expected pattern %q, got %q", "/users/:id", got)
+	}
+}
+
+func TestRouter_RoutePatternEmptyOnNotFound(t *testing.T) {
+	pattern := "unset"
+	r, err := router.New(router.WithNotFound(func(req *http.Request) types.Responder {
+		pattern = router.GetRoutePattern(req.Context())
+		return &testResponder{Status: http.StatusNotFound, Body: "missing"}
+	}))
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+	if pattern != "" {
+		t.Fatalf("expected empty pattern on a miss, got %q", pattern)
+	}
+}
+
+func TestRouter_Handler(t *testing.T) {
+	var calls []string
+	hook := func(next types.Handler) types.Handler {
+		return func(req *http.Request) types.Responder {
+			calls = append(calls, "pre-route")
+			return next(req)
+		}
+	}
+	wrapper := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			calls = append(calls, "wrapper")
+			next.ServeHTTP(w, req)
+		})
+	}
+
+	r, err := router.New(
+		router.WithPreRoute(hook),
+		router.WithHandlerWrapper(wrapper),
+		router.WithDefaultHeaders(http.Header{"x-frame-options": {"DENY"}}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+	r.Prefix("/ping").GET(NewTestHandler(http.StatusOK, "pong"))
+
+	w := httptest.NewRecorder()
+	r.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ping", nil))
+
+	if w.Code != http.StatusOK || w.Body.String() != "pong" {
+		t.Fatalf("expected 200 pong, got %d %q", w.Code, w.Body.String())
+	}
+	if want := []string{"wrapper", "pre-route"}; !slices.Equal(calls, want) {
+		t.Fatalf("want %v, got %v", want, calls)
+	}
+	if got := w.Header().Get("X-Frame-Options"); got != "DENY" {
+		t.Fatalf("expected default header DENY, got %q", got)
+	}
+}
+
+func TestRouter_InvalidSegmentPanics(t *testing.T) {
+	r, err := router.New()
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+
+	defer func() {
+		msg, _ := recover().(string)
+		want := `GET /foo/:: invalid segment ":" in /foo/:`
+		if !strings.HasPrefix(msg, want) {
+			t.Fatalf("expected panic starting with %q, got %q", want, msg)
+		}
+	}()
+	r.Prefix("/foo/:").GET(NewTestHandler(http.StatusOK, "ok"))
+}
+
+func TestRouter_ANY(t *testing.T) {
+	r, err := router.New()
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+	r.Prefix("/proxy/*path").ANY(func(req *http.Request) types.Responder {
+		return &testResponder{Status: http.StatusOK, Body: req.Method}
+	})
+	r.HandleAny("/hook", NewTestHandler(http.StatusAccepted, "hook"))
+
+	for _, method := range router.AnyMethods {
+		t.Run(method, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(method, "/proxy/a/b", nil))
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+			}
+			if method != http.MethodHead && w.Body.String() != method {
+				t.Fatalf("expected body %q, got %q", method, w.Body.String())
+			}
+
+			w = httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(method, "/hook", nil))
+			if w.Code != http.StatusAccepted {
+				t.Fatalf("expected status %d, got %d", http.StatusAccepted, w.Code)
+			}
+		})
+	}
+}
+
+func TestRouter_ANYConflictPanics(t *testing.T) {
+	r, err := router.New()
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+	r.Prefix("/items").DELETE(NewTestHandler(http.StatusOK, "delete"))
+
+	defer func() {
+		msg, _ := recover().(string)
+		want := "ANY /items: a DELETE handler is already registered"
+		if msg != want {
+			t.Fatalf("expected panic %q, got %q", want, msg)
+		}
+	}()
+	r.Prefix("/items").ANY(NewTestHandler(http.StatusOK, "any"))
+}
+
+func TestRouter_Match(t *testing.T) {
+	r, err := router.New()
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+	r.Prefix("/health").GET(NewTestHandler(http.StatusOK, "ok"))
+	r.Prefix("/users/:id").GET(NewTestHandler(http.StatusOK, "user"))
+	r.Prefix("/files/*path").GET(NewTestHandler(http.StatusOK, "file"))
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		wantPattern string
+		wantParams  map[string]string
+		wantFound   bool
+	}{
+		{name: "static", method: http.MethodGet, path: "/health", wantPattern: "/health", wantParams: map[string]string{}, wantFound: true},
+		{name: "param", method: http.MethodGet, path: "/users/42", wantPattern: "/users/:id", wantParams: map[string]string{"id": "42"}, wantFound: true},
+		{name: "wildcard", method: http.MethodGet, path: "/files/a/b.txt", wantPattern: "/files/*path", wantParams: map[string]string{"path": "a/b.txt"}, wantFound: true},
+		{name: "miss", method: http.MethodPost, path: "/users/42", wantFound: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pattern, params, found := r.Match(tt.method, tt.path)
+			if found != tt.wantFound {
+				t.Fatalf("expected found=%v, got %v", tt.wantFound, found)
+			}
+			if !found {
+				return
+			}
+			if pattern != tt.wantPattern {
+				t.Errorf("expected pattern %q, got %q", tt.wantPattern, pattern)
+			}
+			if !maps.Equal(params, tt.wantParams) {
+				t.Errorf("want params %v, got %v", tt.wantParams, params)
+			}
+		})
+	}
+
+	// Match must not mark the router as started, so routes can still be added
+	r.Prefix("/later").GET(NewTestHandler(http.StatusOK, "later"))
+	if r.Config().Started {
+		t.Fatal("expected Match not to start the router")
+	}
+}
+
+type recordingWriter struct {
+	header http.Header
+	writes int
+}
+
+func (rw *recordingWriter) Header() http.Header {
+	return rw.header
+}
+
+func (rw *recordingWriter) Write(b []byte) (int, error) {
+	rw.writes++
+	return len(b), nil
+}
+
+func (rw *recordingWriter) WriteHeader(int) {
+	rw.writes++
+}
+
+func TestRouter_SkipsWriteForCanceledContext(t *testing.T) {
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	r, err := router.New(router.WithSkipCanceled())
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+	ran := false
+	r.Prefix("/slow").GET(func(req *http.Request) types.Responder {
+		ran = true
+		return &testResponder{Status: http.StatusOK, Body: "too late"}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	w := &recordingWriter{header: http.Header{}}
+	for range 3 {
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/slow", nil).WithContext(ctx))
+	}
+
+	if !ran {
+		t.Fatal("expected the handler to run")
+	}
+	if w.writes != 0 {
+		t.Fatalf("expected no write attempts, got %d", w.writes)
+	}
+	if n := strings.Count(logs.String(), "skipped writing"); n != 1 {
+		t.Fatalf("expected one debounced log line, got %d in %q", n, logs.String())
+	}
+}
+
+func TestRouter_ServeStaticMultipleRoots(t *testing.T) {
+	uploads := t.TempDir()
+	if err := os.WriteFile(filepath.Join(uploads, "app.txt"), []byte("from disk"), 0o644); err != nil {
+		t.Fatalf("failed to write upload: %v", err)
+	}
+
+	r, err := router.New()
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+	r.Prefix("/static").ServeStatic(fstest.MapFS{"app.txt": {Data: []byte("from embed")}})
+	r.Prefix("/uploads").ServeStatic(os.DirFS(uploads))
+
+	tests := []struct {
+		path string
+		code int
+		body string
+	}{
+		{"/static/app.txt", http.StatusOK, "from embed"},
+		{"/uploads/app.txt", http.StatusOK, "from disk"},
+		{"/uploads/missing.txt", http.StatusNotFound, "404 page not found\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if w.Code != tt.code {
+				t.Fatalf("expected status %d, got %d", tt.code, w.Code)
+			}
+			if got := w.Body.String(); got != tt.body {
+				t.Errorf("expected body %q, got %q", tt.body, got)
+			}
+		})
+	}
+
+	if got := r.Config().StaticMounts; !slices.Equal(got, []string{"/static", "/uploads"}) {
+		t.Errorf("expected both static mounts, got %v", got)
+	}
+}
+
+func TestRouter_ServeStaticFallThrough(t *testing.T) {
+	r, err := router.New()
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+	api := r.Prefix("/api")
+	api.NotFound(func(req *http.Request) types.Responder {
+		return &testResponder{Status: http.StatusNotFound, Body: "api 404"}
+	})
+	api.Prefix("/docs").ServeStatic(fstest.MapFS{"index.txt": {Data: []byte("docs")}}, responders.StaticOptions{FallThrough: true})
+
+	tests := []struct {
+		path string
+		code int
+		body string
+	}{
+		{"/api/docs/index.txt", http.StatusOK, "docs"},
+		{"/api/docs/missing.txt", http.StatusNotFound, "api 404"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if w.Code != tt.code {
+				t.Fatalf("expected status %d, got %d", tt.code, w.Code)
+			}
+			if got := w.Body.String(); got != tt.body {
+				t.Errorf("expected body %q, got %q", tt.body, got)
+			}
+		})
+	}
+}
+
+func TestRouter_ZeroParamRouteGetParams(t *testing.T) {
+	r, err := router.New()
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+
+	var params map[string]string
+	r.Prefix("/static/route").GET(func(req *http.Request) types.Responder {
+		params = router.GetParams(req.Context())
+		params["added"] = "by handler"
+		return &testResponder{Status: http.StatusOK}
+	})
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/static/route", nil))
+
+	if params == nil {
+		t.Fatal("expected a non-nil params map")
+	}
+	if len(params) != 1 || params["added"] != "by handler" {
+		t.Errorf("expected a usable empty map, got %v", params)
+	}
+}
+
+func BenchmarkRouter_StaticRoute(b *testing.B) {
+	r, _ := router.New()
+	r.Prefix("/api/v1/health").GET(NewTestHandler(http.StatusOK, "ok"))
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/health", nil)
+	w := &discardWriter{header: http.Header{}}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		r.ServeHTTP(w, req)
+	}
+}
+
+type discardWriter struct {
+	header http.Header
+}
+
+func (d *discardWriter) Header() http.Header         { return d.header }
+func (d *discardWriter) Write(p []byte) (int, error) { return len(p), nil }
+func (d *discardWriter) WriteHeader(int)             {}
+
+func TestRouter_WritesForCanceledContextByDefault(t *testing.T) {
+	r, err := router.New()
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+	r.Prefix("/slow").GET(NewTestHandler(http.StatusOK, "written"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/slow", nil).WithContext(ctx))
+
+	if w.Body.String() != "written" {
+		t.Fatalf("expected the response to be written, got %q", w.Body.String())
+	}
+}
+
+func TestRouter_SkipCanceledReleasesPerClientLimit(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	r, err := router.New(router.WithSkipCanceled())
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+	r.Use(router.PerClientLimit(1)).Prefix("/slow").GET(NewTestHandler(http.StatusOK, "ok"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/slow", nil).WithContext(ctx))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/slow", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected the canceled request to release its slot, got status %d", w.Code)
+	}
+}
@@ -0,0 +1,67 @@
+package router
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/elmq0022/kami/responders"
+	"github.com/elmq0022/kami/types"
+)
+
+// sequenceIdleTTL is how long a client's sequence state is kept after its last request.
+const sequenceIdleTTL = 10 * time.Minute
+
+type sequenceEntry struct {
+	last     uint64
+	lastSeen time.Time
+}
+
+// Sequence returns a middleware that enforces in-order delivery of requests per client.
+// Each request must carry a positive integer in tokenHeader that is greater than the last
+// token accepted from the same client IP. Replayed or out-of-order tokens receive
+// 409 Conflict, and missing or malformed tokens receive 400 Bad Request.
+// State for clients idle longer than ten minutes is discarded.
+func Sequence(tokenHeader string) types.Middleware {
+	var mu sync.Mutex
+	clients := make(map[string]*sequenceEntry)
+	lastSweep := time.Now()
+
+	return func(next types.Handler) types.Handler {
+		return func(req *http.Request) types.Responder {
+			token, err := strconv.ParseUint(req.Header.Get(tokenHeader), 10, 64)
+			if err != nil || token == 0 {
+				return responders.JSONErrorResponse("missing or invalid "+tokenHeader, http.StatusBadRequest)
+			}
+
+			now := time.Now()
+			client := remoteHost(req)
+
+			mu.Lock()
+			if now.Sub(lastSweep) > sequenceIdleTTL {
+				for k, e := range clients {
+					if now.Sub(e.lastSeen) > sequenceIdleTTL {
+						delete(clients, k)
+					}
+				}
+				lastSweep = now
+			}
+
+			e, ok := clients[client]
+			if !ok {
+				e = &sequenceEntry{}
+				clients[client] = e
+			}
+			e.lastSeen = now
+			if token <= e.last {
+				mu.Unlock()
+				return responders.JSONErrorResponse("out of order "+tokenHeader, http.StatusConflict)
+			}
+			e.last = token
+			mu.Unlock()
+
+			return next(req)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package router_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/elmq0022/kami/router"
+)
+
+func TestSequence(t *testing.T) {
+	r, _ := router.New()
+	r.Use(router.Sequence("X-Sequence")).Prefix("/events").POST(NewTestHandler(http.StatusOK, "ok"))
+
+	send := func(remoteAddr, token string) int {
+		req := httptest.NewRequest(http.MethodPost, "/events", nil)
+		req.RemoteAddr = remoteAddr
+		if token != "" {
+			req.Header.Set("X-Sequence", token)
+		}
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	steps := []struct {
+		name       string
+		remoteAddr string
+		token      string
+		wantStatus int
+	}{
+		{name: "first token", remoteAddr: "192.0.2.1:1000", token: "1", wantStatus: http.StatusOK},
+		{name: "next token", remoteAddr: "192.0.2.1:1001", token: "2", wantStatus: http.StatusOK},
+		{name: "gap is allowed", remoteAddr: "192.0.2.1:1002", token: "5", wantStatus: http.StatusOK},
+		{name: "out of order", remoteAddr: "192.0.2.1:1003", token: "4", wantStatus: http.StatusConflict},
+		{name: "replayed", remoteAddr: "192.0.2.1:1004", token: "5", wantStatus: http.StatusConflict},
+		{name: "other client independent", remoteAddr: "192.0.2.2:1000", token: "1", wantStatus: http.StatusOK},
+		{name: "missing token", remoteAddr: "192.0.2.1:1005", token: "", wantStatus: http.StatusBadRequest},
+		{name: "malformed token", remoteAddr: "192.0.2.1:1006", token: "abc", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, step := range steps {
+		if got := send(step.remoteAddr, step.token); got != step.wantStatus {
+			t.Fatalf("%s: want %d, got %d", step.name, step.wantStatus, got)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package router
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/elmq0022/kami/responders"
+	"github.com/elmq0022/kami/types"
+)
+
+const sessionKey contextKey = "sessionKey"
+
+// SessionStore persists session values by session ID.
+type SessionStore interface {
+	// Load returns the values stored for id, or false if the session does not exist or has expired.
+	Load(id string) (map[string]any, bool)
+	// Save stores values for id, replacing any previous values.
+	Save(id string, values map[string]any) error
+}
+
+// SessionConfig configures the Session middleware and the cookie it issues.
+type SessionConfig struct {
+	// CookieName is the name of the session cookie. Defaults to "kami_session".
+	CookieName string
+	// Path is the cookie path. Defaults to "/".
+	Path string
+	// MaxAge sets the cookie lifetime. Zero issues a browser-session cookie.
+	MaxAge time.Duration
+	Secure bool
+	// DisableHttpOnly lets scripts read the session cookie. The cookie is HttpOnly by default.
+	DisableHttpOnly bool
+	SameSite        http.SameSite
+}
+
+// SessionData holds the values of one client session. It is safe for concurrent use.
+type SessionData struct {
+	ID string
+
+	mu     sync.Mutex
+	values map[string]any
+	dirty  bool
+}
+
+// Get returns the value stored under key, or nil if there is none.
+func (s *SessionData) Get(key string) any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.values[key]
+}
+
+// Set stores value under key and marks the session for saving.
+func (s *SessionData) Set(key string, value any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[key] = value
+	s.dirty = true
+}
+
+// Delete removes key and marks the session for saving.
+func (s *SessionData) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.values, key)
+	s.dirty = true
+}
+
+// Session returns a middleware that loads the session named by the session cookie from store
+// and makes it available through GetSession. Requests without a valid session cookie get a
+// new session, which is only saved and sent in a Set-Cookie header once a value is set, so
+// cookieless clients such as health probes do not create sessions. Modified sessions are saved
+// after the handler runs, before the response is written; changes made while the response is
+// being written are not saved. If saving fails, the error is logged and the response is replaced
+// by a 500, since the handler's changes were lost.
+func Session(store SessionStore, cfg SessionConfig) types.Middleware {
+	if cfg.CookieName == "" {
+		cfg.CookieName = "kami_session"
+	}
+	if cfg.Path == "" {
+		cfg.Path = "/"
+	}
+
+	return func(next types.Handler) types.Handler {
+		return func(req *http.Request) types.Responder {
+			var sess *SessionData
+			if c, err := req.Cookie(cfg.CookieName); err == nil {
+				if values, ok := store.Load(c.Value); ok {
+					sess = &SessionData{ID: c.Value, values: values}
+				}
+			}
+			isNew := sess == nil
+			if isNew {
+				sess = &SessionData{ID: newSessionID(), values: make(map[string]any)}
+			}
+
+			ctx := context.WithValue(req.Context(), sessionKey, sess)
+			responder := next(req.WithContext(ctx))
+
+			sess.mu.Lock()
+			saved := sess.dirty
+			var err error
+			if saved {
+				err = store.Save(sess.ID, sess.values)
+				sess.dirty = false
+			}
+			sess.mu.Unlock()
+
+			if err != nil {
+				log.Printf("session: saving %s %s: %v", req.Method, req.URL.Path, err)
+				return responders.JSONErrorResponse(http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}
+			if !isNew || !saved {
+				return responder
+			}
+			return responders.WithCookie(responder, sessionCookie(cfg, sess.ID))
+		}
+	}
+}
+
+// GetSession returns the session loaded by the Session middleware, or nil outside of it.
+func GetSession(ctx context.Context) *SessionData {
+	s, _ := ctx.Value(sessionKey).(*SessionData)
+	return s
+}
+
+func sessionCookie(cfg SessionConfig, id string) *http.Cookie {
+	c := &http.Cookie{
+		Name:     cfg.CookieName,
+		Value:    id,
+		Path:     cfg.Path,
+		Secure:   cfg.Secure,
+		HttpOnly: !cfg.DisableHttpOnly,
+		SameSite: cfg.SameSite,
+	}
+	if cfg.MaxAge > 0 {
+		c.MaxAge = int(cfg.MaxAge.Seconds())
+	}
+	return c
+}
+
+func newSessionID() string {
+	var b [32]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+type memorySession struct {
+	values  map[string]any
+	expires time.Time
+}
+
+// MemorySessionStore is an in-memory SessionStore. Sessions expire ttl after they were last saved.
+// Expired sessions are evicted lazily, so the store suits single-instance deployments and tests.
+type MemorySessionStore struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	sessions  map[string]memorySession
+	lastSweep time.Time
+}
+
+// NewMemorySessionStore creates a MemorySessionStore whose sessions live for ttl.
+func NewMemorySessionStore(ttl time.Duration) *MemorySessionStore {
+	return &MemorySessionStore{
+		ttl:       ttl,
+		sessions:  make(map[string]memorySession),
+		lastSweep: time.Now(),
+	}
+}
+
+// Load returns a copy of the values saved for id.
+func (m *MemorySessionStore) Load(id string) (map[string]any, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, ok := m.sessions[id]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(s.expires) {
+		delete(m.sessions, id)
+		return nil, false
+	}
+	return copyValues(s.values), true
+}
+
+// Save stores a copy of values for id and resets its expiry.
+func (m *MemorySessionStore) Save(id string, values map[string]any) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(m.lastSweep) > m.ttl {
+		for k, s := range m.sessions {
+			if now.After(s.expires) {
+				delete(m.sessions, k)
+			}
+		}
+		m.lastSweep = now
+	}
+
+	m.sessions[id] = memorySession{values: copyValues(values), expires: now.Add(m.ttl)}
+	return nil
+}
+
+func copyValues(values map[string]any) map[string]any {
+	c := make(map[string]any, len(values))
+	for k, v := range values {
+		c[k] = v
+	}
+	return c
+}
@@ -0,0 +1,158 @@
+package router_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/elmq0022/kami/router"
+	"github.com/elmq0022/kami/types"
+)
+
+func newSessionRouter(t *testing.T, store router.SessionStore) *router.Router {
+	t.Helper()
+	r, err := router.New()
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+
+	cfg := router.SessionConfig{Secure: true, SameSite: http.SameSiteLaxMode}
+	r.Use(router.Session(store, cfg)).Prefix("/visit").GET(func(req *http.Request) types.Responder {
+		sess := router.GetSession(req.Context())
+		visits, _ := sess.Get("visits").(int)
+		visits++
+		sess.Set("visits", visits)
+		return &testResponder{Status: http.StatusOK, Body: fmt.Sprint(visits)}
+	})
+	return r
+}
+
+func TestSession_New(t *testing.T) {
+	r := newSessionRouter(t, router.NewMemorySessionStore(time.Hour))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/visit", nil))
+
+	if w.Body.String() != "1" {
+		t.Fatalf("expected first visit, got %q", w.Body.String())
+	}
+
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("expected 1 cookie, got %d", len(cookies))
+	}
+	c := cookies[0]
+	if c.Name != "kami_session" || c.Value == "" {
+		t.Fatalf("expected kami_session cookie with an ID, got %s=%q", c.Name, c.Value)
+	}
+	if !c.HttpOnly || !c.Secure || c.SameSite != http.SameSiteLaxMode {
+		t.Errorf("expected HttpOnly, Secure and SameSite=Lax, got %+v", c)
+	}
+}
+
+func TestSession_LoadExisting(t *testing.T) {
+	r := newSessionRouter(t, router.NewMemorySessionStore(time.Hour))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/visit", nil))
+	cookie := w.Result().Cookies()[0]
+
+	req := httptest.NewRequest(http.MethodGet, "/visit", nil)
+	req.AddCookie(cookie)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Body.String() != "2" {
+		t.Fatalf("expected second visit, got %q", w.Body.String())
+	}
+	if len(w.Result().Cookies()) != 0 {
+		t.Errorf("expected no Set-Cookie for an existing session")
+	}
+}
+
+func TestSession_UnknownCookieStartsNewSession(t *testing.T) {
+	r := newSessionRouter(t, router.NewMemorySessionStore(time.Hour))
+
+	req := httptest.NewRequest(http.MethodGet, "/visit", nil)
+	req.AddCookie(&http.Cookie{Name: "kami_session", Value: "forged"})
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Body.String() != "1" {
+		t.Fatalf("expected a fresh session, got %q", w.Body.String())
+	}
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Value == "forged" {
+		t.Fatalf("expected a newly issued session ID, got %v", cookies)
+	}
+}
+
+func TestSession_UnmodifiedNewSessionIsNotSaved(t *testing.T) {
+	store := &recordingStore{}
+	r, err := router.New()
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+	r.Use(router.Session(store, router.SessionConfig{})).Prefix("/healthz").GET(NewTestHandler(http.StatusOK, "ok"))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	if store.saves != 0 {
+		t.Errorf("expected no save for an untouched session, got %d", store.saves)
+	}
+	if len(w.Result().Cookies()) != 0 {
+		t.Errorf("expected no session cookie for an untouched session")
+	}
+}
+
+func TestSession_SaveErrorReturns500(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	r := newSessionRouter(t, &recordingStore{err: errors.New("store unavailable")})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/visit", nil))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+	if len(w.Result().Cookies()) != 0 {
+		t.Errorf("expected no cookie for a session that was not persisted")
+	}
+}
+
+type recordingStore struct {
+	saves int
+	err   error
+}
+
+func (s *recordingStore) Load(id string) (map[string]any, bool) {
+	return nil, false
+}
+
+func (s *recordingStore) Save(id string, values map[string]any) error {
+	s.saves++
+	return s.err
+}
+
+func TestMemorySessionStore_Expiry(t *testing.T) {
+	store := router.NewMemorySessionStore(10 * time.Millisecond)
+	store.Save("abc", map[string]any{"k": "v"})
+
+	if _, ok := store.Load("abc"); !ok {
+		t.Fatal("expected session to load before expiry")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if _, ok := store.Load("abc"); ok {
+		t.Fatal("expected session to be evicted after ttl")
+	}
+}
@@ -0,0 +1,52 @@
+package router
+
+import (
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// skipLogInterval is the minimum time between log lines about skipped responses.
+const skipLogInterval = 10 * time.Second
+
+// skipLog reports responses skipped because the request context was canceled.
+// Skips are counted and logged at most once per skipLogInterval so that a burst of
+// client disconnects does not flood the log.
+type skipLog struct {
+	mu      sync.Mutex
+	last    time.Time
+	skipped int
+}
+
+func (s *skipLog) record(req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.skipped++
+	now := time.Now()
+	if now.Sub(s.last) < skipLogInterval {
+		return
+	}
+
+	log.Printf("skipped writing %d response(s) for canceled requests, latest %s %s: %v",
+		s.skipped, req.Method, req.URL.Path, req.Context().Err())
+	s.skipped = 0
+	s.last = now
+}
+
+// canceledWriter stands in for the ResponseWriter of a canceled request, accepting and
+// dropping everything the responder writes.
+type canceledWriter struct {
+	header http.Header
+}
+
+func (cw *canceledWriter) Header() http.Header {
+	return cw.header
+}
+
+func (cw *canceledWriter) Write(p []byte) (int, error) {
+	return len(p), nil
+}
+
+func (cw *canceledWriter) WriteHeader(int) {}
@@ -0,0 +1,46 @@
+package router
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/elmq0022/kami/types"
+)
+
+// SlowRequestWarn returns a middleware that logs a warning with the method, path, status and
+// duration of any request that takes longer than threshold, measured like Logger from the
+// handler call until the response has been written. Faster requests are not logged, and
+// the response is never altered.
+func SlowRequestWarn(threshold time.Duration) types.Middleware {
+	return func(next types.Handler) types.Handler {
+		return func(req *http.Request) types.Responder {
+			start := time.Now()
+			responder := next(req)
+			return &slowRequestResponder{
+				inner:     responder,
+				method:    req.Method,
+				path:      req.URL.Path,
+				start:     start,
+				threshold: threshold,
+			}
+		}
+	}
+}
+
+type slowRequestResponder struct {
+	inner     types.Responder
+	method    string
+	path      string
+	start     time.Time
+	threshold time.Duration
+}
+
+func (s *slowRequestResponder) Respond(w http.ResponseWriter, req *http.Request) {
+	lw := &loggingWriter{ResponseWriter: w, statusCode: http.StatusOK}
+	s.inner.Respond(lw, req)
+
+	if duration := time.Since(s.start); duration > s.threshold {
+		log.Printf("WARN slow request: %s %s - %d (%v, threshold %v)", s.method, s.path, lw.statusCode, duration, s.threshold)
+	}
+}
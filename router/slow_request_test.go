@@ -0,0 +1,54 @@
+package router_test
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elmq0022/kami/router"
+	"github.com/elmq0022/kami/types"
+)
+
+func TestSlowRequestWarn(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	r, err := router.New()
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+	watched := r.Use(router.SlowRequestWarn(20 * time.Millisecond))
+	watched.Prefix("/slow").GET(func(req *http.Request) types.Responder {
+		time.Sleep(30 * time.Millisecond)
+		return &testResponder{Status: http.StatusAccepted, Body: "slow"}
+	})
+	watched.Prefix("/fast").GET(NewTestHandler(http.StatusOK, "fast"))
+
+	t.Run("slow request warns", func(t *testing.T) {
+		buf.Reset()
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/slow", nil))
+
+		if w.Code != http.StatusAccepted || w.Body.String() != "slow" {
+			t.Errorf("expected response to be unchanged, got %d %q", w.Code, w.Body.String())
+		}
+		if got := buf.String(); !strings.Contains(got, "slow request: GET /slow - 202") {
+			t.Errorf("expected a slow request warning, got %q", got)
+		}
+	})
+
+	t.Run("fast request is quiet", func(t *testing.T) {
+		buf.Reset()
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/fast", nil))
+
+		if buf.Len() != 0 {
+			t.Errorf("expected no log output, got %q", buf.String())
+		}
+	})
+}
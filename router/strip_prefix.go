@@ -0,0 +1,56 @@
+package router
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/elmq0022/kami/types"
+)
+
+// StripPrefix returns a middleware that removes prefix from the request path before calling
+// the next handler. Requests whose path is not under prefix pass through unchanged.
+//
+// Installed with UseGlobal or WithPreRoute, it affects routing, so routes are registered
+// without the prefix. As route middleware it lets a mounted sub-router see the paths it expects:
+//
+//	api, _ := router.New()
+//	api.Prefix("/users").GET(listUsers)
+//	r.Prefix("/api/*rest").ANY(router.FromHTTPHandler(api), router.StripPrefix("/api"))
+//
+// The responder is bound to the stripped request, so handlers that write during Respond see it too.
+func StripPrefix(prefix string) types.Middleware {
+	prefix = "/" + strings.Trim(prefix, "/")
+
+	return func(next types.Handler) types.Handler {
+		return func(req *http.Request) types.Responder {
+			path, ok := stripPathPrefix(req.URL.Path, prefix)
+			if !ok || prefix == "/" {
+				return next(req)
+			}
+
+			r2 := new(http.Request)
+			*r2 = *req
+			r2.URL = new(url.URL)
+			*r2.URL = *req.URL
+			r2.URL.Path = path
+			r2.URL.RawPath = ""
+			if raw, ok := stripPathPrefix(req.URL.RawPath, prefix); ok {
+				r2.URL.RawPath = raw
+			}
+			return &routedResponder{inner: next(r2), req: r2}
+		}
+	}
+}
+
+// stripPathPrefix removes prefix from path at a segment boundary, keeping a leading slash.
+func stripPathPrefix(path, prefix string) (string, bool) {
+	rest, ok := strings.CutPrefix(path, prefix)
+	if !ok || (rest != "" && rest[0] != '/') {
+		return path, false
+	}
+	if rest == "" {
+		rest = "/"
+	}
+	return rest, true
+}
@@ -0,0 +1,77 @@
+package router_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/elmq0022/kami/router"
+	"github.com/elmq0022/kami/types"
+)
+
+func pathHandler(req *http.Request) types.Responder {
+	return &testResponder{Status: http.StatusOK, Body: req.URL.Path + " " + router.GetParams(req.Context())["id"]}
+}
+
+func TestStripPrefix_MountedRouter(t *testing.T) {
+	api, err := router.New()
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+	api.Prefix("/users").GET(pathHandler)
+	api.Prefix("/users/:id").GET(pathHandler)
+
+	r, err := router.New()
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+	r.Prefix("/api/*rest").ANY(router.FromHTTPHandler(api), router.StripPrefix("/api"))
+
+	tests := []struct {
+		path string
+		code int
+		body string
+	}{
+		{"/api/users", http.StatusOK, "/users "},
+		{"/api/users/42", http.StatusOK, "/users/42 42"},
+		{"/api/missing", http.StatusNotFound, "Not Found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if w.Code != tt.code {
+				t.Fatalf("expected status %d, got %d", tt.code, w.Code)
+			}
+			if got := w.Body.String(); got != tt.body {
+				t.Errorf("expected body %q, got %q", tt.body, got)
+			}
+		})
+	}
+}
+
+func TestStripPrefix_Global(t *testing.T) {
+	r, err := router.New(router.WithPreRoute(router.StripPrefix("/app/")))
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+	r.Prefix("/users").GET(pathHandler)
+
+	for _, path := range []string{"/app/users", "/users"} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+		if w.Code != http.StatusOK || w.Body.String() != "/users " {
+			t.Errorf("%s: expected 200 %q, got %d %q", path, "/users ", w.Code, w.Body.String())
+		}
+	}
+
+	// A path that only shares a string prefix is not stripped
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/application/users", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 for /application/users, got %d", w.Code)
+	}
+}
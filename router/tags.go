@@ -0,0 +1,45 @@
+package router
+
+import (
+	"context"
+	"net/http"
+	"slices"
+
+	"github.com/elmq0022/kami/types"
+)
+
+const routeTagsKey contextKey = "routeTagsKey"
+
+// withTags stores the route's tags in the context before any of its middleware runs.
+func withTags(next types.Handler, tags []string) types.Handler {
+	return func(req *http.Request) types.Responder {
+		ctx := context.WithValue(req.Context(), routeTagsKey, tags)
+		return next(req.WithContext(ctx))
+	}
+}
+
+// GetRouteTags returns the tags of the matched route, as set with Router.Tag.
+// Returns an empty slice for untagged routes.
+func GetRouteTags(ctx context.Context) []string {
+	if t, ok := ctx.Value(routeTagsKey).([]string); ok {
+		return t
+	}
+	return []string{}
+}
+
+// IfTag returns a middleware that applies mw only to routes tagged with tag.
+// Other routes skip mw and go straight to the next handler.
+//
+//	r = r.Use(router.IfTag("admin", requireAdmin))
+//	r.Tag("admin").Prefix("/admin/users").GET(listUsers)
+func IfTag(tag string, mw types.Middleware) types.Middleware {
+	return func(next types.Handler) types.Handler {
+		wrapped := mw(next)
+		return func(req *http.Request) types.Responder {
+			if slices.Contains(GetRouteTags(req.Context()), tag) {
+				return wrapped(req)
+			}
+			return next(req)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package router_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/elmq0022/kami/router"
+	"github.com/elmq0022/kami/types"
+)
+
+func TestIfTag(t *testing.T) {
+	r, err := router.New()
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+
+	api := r.Use(router.IfTag("audited", testMiddleware1))
+	api.Tag("audited").Prefix("/tagged").GET(NewTestHandler(http.StatusOK, "tagged"))
+	api.Prefix("/untagged").GET(NewTestHandler(http.StatusOK, "untagged"))
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "/tagged", want: "tagged1"},
+		{path: "/untagged", want: "untagged"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if w.Body.String() != tt.want {
+				t.Fatalf("want %q, got %q", tt.want, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestGetRouteTags(t *testing.T) {
+	r, err := router.New()
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+
+	var got []string
+	r.Tag("public").Tag("v2").Prefix("/items").GET(func(req *http.Request) types.Responder {
+		got = router.GetRouteTags(req.Context())
+		return &testResponder{Status: http.StatusOK}
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items", nil))
+
+	if want := []string{"public", "v2"}; !slices.Equal(got, want) {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+}
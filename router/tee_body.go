@@ -0,0 +1,41 @@
+package router
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	"github.com/elmq0022/kami/types"
+)
+
+// TeeBodyLimit is the most bytes of a request body TeeBody hands to its sink.
+const TeeBodyLimit = 64 << 10
+
+// TeeBody returns a middleware that passes a copy of the request body to sink, for example
+// for audit logging, without consuming it: the handler still reads the full body. At most
+// TeeBodyLimit bytes are buffered, so sink receives a truncated copy of larger bodies while
+// the remainder streams to the handler untouched. Requests without a body are not passed to sink.
+func TeeBody(sink func(*http.Request, []byte)) types.Middleware {
+	return func(next types.Handler) types.Handler {
+		return func(req *http.Request) types.Responder {
+			if req.Body == nil || req.Body == http.NoBody {
+				return next(req)
+			}
+
+			// A read error is left for the handler to encounter on the original body
+			captured, _ := io.ReadAll(io.LimitReader(req.Body, TeeBodyLimit))
+			sink(req, captured)
+
+			r2 := new(http.Request)
+			*r2 = *req
+			r2.Body = &teeBody{Reader: io.MultiReader(bytes.NewReader(captured), req.Body), Closer: req.Body}
+			return next(r2)
+		}
+	}
+}
+
+// teeBody replays the captured prefix before the rest of the original body.
+type teeBody struct {
+	io.Reader
+	io.Closer
+}
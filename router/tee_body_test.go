@@ -0,0 +1,59 @@
+package router_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/elmq0022/kami/router"
+	"github.com/elmq0022/kami/types"
+)
+
+func TestTeeBody(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		wantSink string
+	}{
+		{"small body", `{"amount":42}`, `{"amount":42}`},
+		{"body over the limit", strings.Repeat("x", router.TeeBodyLimit+10), strings.Repeat("x", router.TeeBodyLimit)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sunk, handled string
+			var sinkPath string
+
+			r, err := router.New()
+			if err != nil {
+				t.Fatalf("failed to create router: %v", err)
+			}
+			audit := router.TeeBody(func(req *http.Request, body []byte) {
+				sinkPath = req.URL.Path
+				sunk = string(body)
+			})
+			r.Use(audit).Prefix("/payments").POST(func(req *http.Request) types.Responder {
+				data, err := io.ReadAll(req.Body)
+				if err != nil {
+					t.Errorf("handler failed to read body: %v", err)
+				}
+				handled = string(data)
+				return &testResponder{Status: http.StatusOK}
+			})
+
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/payments", strings.NewReader(tt.body)))
+
+			if sinkPath != "/payments" {
+				t.Errorf("expected sink to receive the request, got path %q", sinkPath)
+			}
+			if sunk != tt.wantSink {
+				t.Errorf("expected sink to receive %d bytes, got %d", len(tt.wantSink), len(sunk))
+			}
+			if handled != tt.body {
+				t.Errorf("expected handler to read the full %d byte body, got %d bytes", len(tt.body), len(handled))
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package router
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/elmq0022/kami/responders"
+	"github.com/elmq0022/kami/types"
+)
+
+// WithRouteTimeout bounds how long a single route's handler may run. Pass it in the
+// route-specific middleware slot when registering the route:
+//
+//	r.Prefix("/slow").GET(handler, router.WithRouteTimeout(2*time.Second))
+//
+// The handler receives a request whose context is canceled after d. If it has not returned
+// by then, the client receives 503 Service Unavailable and the handler's eventual result is discarded.
+// A panic in the handler is propagated to the router's panic recovery.
+func WithRouteTimeout(d time.Duration) types.Middleware {
+	return func(next types.Handler) types.Handler {
+		return func(req *http.Request) types.Responder {
+			ctx, cancel := context.WithTimeout(req.Context(), d)
+			defer cancel()
+
+			type result struct {
+				responder types.Responder
+				panicked  any
+			}
+			done := make(chan result, 1)
+			go func() {
+				defer func() {
+					if p := recover(); p != nil {
+						done <- result{panicked: p}
+					}
+				}()
+				done <- result{responder: next(req.WithContext(ctx))}
+			}()
+
+			select {
+			case res := <-done:
+				if res.panicked != nil {
+					panic(res.panicked)
+				}
+				return res.responder
+			case <-ctx.Done():
+				return responders.JSONErrorResponse(
+					http.StatusText(http.StatusServiceUnavailable),
+					http.StatusServiceUnavailable,
+				)
+			}
+		}
+	}
+}
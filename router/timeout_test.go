@@ -0,0 +1,75 @@
+package router_test
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/elmq0022/kami/router"
+	"github.com/elmq0022/kami/types"
+)
+
+func TestWithRouteTimeout(t *testing.T) {
+	r, err := router.New()
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+
+	canceled := make(chan struct{})
+	r.Prefix("/slow").GET(func(req *http.Request) types.Responder {
+		<-req.Context().Done()
+		close(canceled)
+		return &testResponder{Status: http.StatusOK, Body: "slow"}
+	}, router.WithRouteTimeout(20*time.Millisecond))
+	r.Prefix("/fast").GET(NewTestHandler(http.StatusOK, "fast"))
+	r.Prefix("/quick").GET(NewTestHandler(http.StatusOK, "quick"), router.WithRouteTimeout(time.Second))
+
+	t.Run("slow route times out", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/slow", nil))
+
+		if w.Code != http.StatusServiceUnavailable {
+			t.Fatalf("expected status %d, got %d", http.StatusServiceUnavailable, w.Code)
+		}
+		select {
+		case <-canceled:
+		case <-time.After(time.Second):
+			t.Fatal("expected the handler's context to be canceled")
+		}
+	})
+
+	for _, path := range []string{"/fast", "/quick"} {
+		t.Run(path+" unaffected", func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+			if w.Code != http.StatusOK || w.Body.String() != path[1:] {
+				t.Fatalf("expected 200 %q, got %d %q", path[1:], w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestWithRouteTimeout_PanicPropagates(t *testing.T) {
+	log.SetOutput(&bytes.Buffer{})
+	defer log.SetOutput(os.Stderr)
+
+	r, err := router.New()
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+	r.Prefix("/panic").GET(func(req *http.Request) types.Responder {
+		panic("boom")
+	}, router.WithRouteTimeout(time.Second))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
@@ -0,0 +1,30 @@
+package router
+
+import (
+	"net/http"
+	"runtime"
+
+	"github.com/elmq0022/kami/responders"
+	"github.com/elmq0022/kami/types"
+)
+
+// VersionInfo describes the running build, typically populated via -ldflags -X.
+type VersionInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"buildTime"`
+	// GoVersion defaults to runtime.Version() when empty.
+	GoVersion string `json:"goVersion"`
+}
+
+// VersionEndpoint registers a GET route at path, relative to the router's prefix, that serves
+// info as JSON. It is an ordinary route, so middleware added with Use applies to it.
+func (r *Router) VersionEndpoint(path string, info VersionInfo) {
+	if info.GoVersion == "" {
+		info.GoVersion = runtime.Version()
+	}
+
+	r.Prefix(path).GET(func(req *http.Request) types.Responder {
+		return responders.JSONResponse(info, http.StatusOK)
+	})
+}
@@ -0,0 +1,59 @@
+package router_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/elmq0022/kami/router"
+	"github.com/elmq0022/kami/types"
+)
+
+type headerResponder struct {
+	inner types.Responder
+}
+
+func (h *headerResponder) Respond(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("X-Middleware", "applied")
+	h.inner.Respond(w, req)
+}
+
+func testMiddlewareHeader(next types.Handler) types.Handler {
+	return func(req *http.Request) types.Responder {
+		return &headerResponder{inner: next(req)}
+	}
+}
+
+func TestVersionEndpoint(t *testing.T) {
+	r, err := router.New()
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+	r.Use(testMiddlewareHeader).VersionEndpoint("/version", router.VersionInfo{
+		Version:   "v1.2.3",
+		Commit:    "abc1234",
+		BuildTime: "2024-06-01T00:00:00Z",
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/version", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if w.Header().Get("X-Middleware") != "applied" {
+		t.Error("expected router middleware to apply to the version route")
+	}
+
+	var got router.VersionInfo
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("failed to decode version info: %v", err)
+	}
+	if got.Commit != "abc1234" {
+		t.Errorf("expected commit abc1234, got %q", got.Commit)
+	}
+	if got.GoVersion == "" {
+		t.Error("expected a Go version")
+	}
+}